@@ -0,0 +1,354 @@
+package githubreleasedownloader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+	"go.uber.org/zap"
+)
+
+// ArchiveFS 压缩包内容的只读文件系统，不需要解压到磁盘
+// zip使用随机访问读取；tar在打开时建立索引并按偏移读取，压缩的tar在打开时解压一次到临时文件，
+// 之后打开其中的文件不需要再次解压。使用完毕后需要调用 Close，同时删除临时文件
+type ArchiveFS struct {
+	fs.FS
+	closer io.Closer
+}
+
+// Close 关闭压缩包文件，删除解压产生的临时文件
+func (a *ArchiveFS) Close() error {
+	return a.closer.Close()
+}
+
+// OpenArchive 打开本地的压缩包，支持 .zip、.tar、.tar.gz（.tgz）、.tar.bz2（.tbz2）、.tar.xz（.txz）和 .tar.zst（.tzst）
+// 不支持的格式返回包装了 ErrUnsupportedArchive 的错误
+func OpenArchive(filePath string) (*ArchiveFS, error) {
+	lowerPath := strings.ToLower(filePath)
+
+	switch {
+	case strings.HasSuffix(lowerPath, ".zip"):
+		reader, err := zip.OpenReader(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("打开zip文件失败: %w", err)
+		}
+		return &ArchiveFS{FS: reader, closer: reader}, nil
+	case strings.HasSuffix(lowerPath, ".tar"):
+		return openTarArchive(filePath, nil)
+	case strings.HasSuffix(lowerPath, ".tar.gz"), strings.HasSuffix(lowerPath, ".tgz"):
+		return openTarArchive(filePath, func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) })
+	case strings.HasSuffix(lowerPath, ".tar.bz2"), strings.HasSuffix(lowerPath, ".tbz2"):
+		return openTarArchive(filePath, func(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(bzip2.NewReader(r)), nil })
+	case strings.HasSuffix(lowerPath, ".tar.xz"), strings.HasSuffix(lowerPath, ".txz"):
+		return openTarArchive(filePath, func(r io.Reader) (io.ReadCloser, error) {
+			reader, err := xz.NewReader(r)
+			if err != nil {
+				return nil, err
+			}
+			return io.NopCloser(reader), nil
+		})
+	case strings.HasSuffix(lowerPath, ".tar.zst"), strings.HasSuffix(lowerPath, ".tzst"):
+		return openTarArchive(filePath, func(r io.Reader) (io.ReadCloser, error) {
+			decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+			if err != nil {
+				return nil, err
+			}
+			return decoder.IOReadCloser(), nil
+		})
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedArchive, filepath.Base(filePath))
+	}
+}
+
+// OpenArchiveAsset 下载Release中指定名称的压缩包资产到缓存目录，返回其内容的文件系统
+// tag为空时使用最新版本，也可以是semver约束。下载与文件下载使用相同的认证、代理、镜像和带宽限制
+func (c *Client) OpenArchiveAsset(ctx context.Context, owner, repo, tag, assetName string) (*ArchiveFS, error) {
+	asset, releaseTag, err := c.findAsset(ctx, owner, repo, tag, assetName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = withEventScope(ctx, owner, repo, releaseTag)
+	filePath, err := c.downloadAsset(ctx, asset, c.releaseDir(owner, repo, releaseTag))
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := OpenArchive(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("已打开压缩包",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.String("asset", assetName),
+		zap.String("path", filePath),
+	)
+	return archive, nil
+}
+
+// decompressFunc 为tar创建解压reader，为nil时表示未压缩
+type decompressFunc func(io.Reader) (io.ReadCloser, error)
+
+// openTarArchive 读取tar的所有文件头并建立索引，压缩的tar先解压到临时文件
+func openTarArchive(filePath string, decompress decompressFunc) (*ArchiveFS, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开tar文件失败: %w", err)
+	}
+
+	var closer io.Closer = file
+	if decompress != nil {
+		spilled, err := spillTar(file, decompress)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		file, closer = spilled, &tempFile{File: spilled}
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		closer.Close()
+		return nil, fmt.Errorf("读取tar文件信息失败: %w", err)
+	}
+
+	tfs := &tarFS{file: file, size: info.Size(), entries: map[string]*tarEntry{
+		".": {name: ".", mode: fs.ModeDir | 0755},
+	}}
+	if err := tfs.index(); err != nil {
+		closer.Close()
+		return nil, err
+	}
+
+	return &ArchiveFS{FS: tfs, closer: closer}, nil
+}
+
+// spillTar 将压缩的tar解压到临时文件，之后按偏移随机读取，避免每次打开文件都从头解压
+func spillTar(file *os.File, decompress decompressFunc) (*os.File, error) {
+	reader, err := decompress(file)
+	if err != nil {
+		return nil, fmt.Errorf("读取tar文件失败: %w", err)
+	}
+	defer reader.Close()
+
+	spilled, err := os.CreateTemp("", "grd-archive-*.tar")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	if _, err := io.Copy(spilled, reader); err != nil {
+		(&tempFile{File: spilled}).Close()
+		return nil, fmt.Errorf("解压tar文件失败: %w", err)
+	}
+
+	return spilled, nil
+}
+
+// tempFile 关闭时删除的临时文件
+type tempFile struct {
+	*os.File
+}
+
+// Close 关闭并删除临时文件
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	if removeErr := os.Remove(f.Name()); err == nil {
+		err = removeErr
+	}
+	return err
+}
+
+// tarFS 基于索引的tar文件系统
+type tarFS struct {
+	file    *os.File             // 未压缩的tar文件
+	size    int64                // tar文件的大小
+	entries map[string]*tarEntry // 按路径索引的文件和目录
+}
+
+// tarEntry tar中的一个文件或目录
+type tarEntry struct {
+	name     string
+	mode     fs.FileMode
+	size     int64
+	modTime  time.Time
+	offset   int64    // 文件内容在tar中的偏移
+	children []string // 目录中的子项名称
+}
+
+// index 遍历tar建立索引，只收录普通文件和目录，父目录不存在时自动补全
+func (t *tarFS) index() error {
+	counter := &countingReader{reader: io.NewSectionReader(t.file, 0, t.size)}
+	reader := tar.NewReader(counter)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("读取tar文件头失败: %w", err)
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		if name == "" || !fs.ValidPath(name) {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			t.addDir(name, header.ModTime)
+		case tar.TypeReg:
+			t.addDir(path.Dir(name), time.Time{})
+			if _, exists := t.entries[name]; !exists {
+				t.addChild(name)
+			}
+			t.entries[name] = &tarEntry{
+				name:    path.Base(name),
+				mode:    fs.FileMode(header.Mode).Perm(),
+				size:    header.Size,
+				modTime: header.ModTime,
+				offset:  counter.n,
+			}
+		}
+	}
+
+	for _, entry := range t.entries {
+		sort.Strings(entry.children)
+	}
+	return nil
+}
+
+// addDir 添加目录及其所有父目录
+func (t *tarFS) addDir(name string, modTime time.Time) {
+	if entry, exists := t.entries[name]; exists {
+		if !modTime.IsZero() {
+			entry.modTime = modTime
+		}
+		return
+	}
+
+	t.addDir(path.Dir(name), time.Time{})
+	t.entries[name] = &tarEntry{name: path.Base(name), mode: fs.ModeDir | 0755, modTime: modTime}
+	t.addChild(name)
+}
+
+// addChild 将路径登记到父目录的子项中
+func (t *tarFS) addChild(name string) {
+	parent := t.entries[path.Dir(name)]
+	parent.children = append(parent.children, path.Base(name))
+}
+
+// Open 实现 fs.FS
+func (t *tarFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	entry, ok := t.entries[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	if entry.mode.IsDir() {
+		return &tarDir{fs: t, path: name, entry: entry}, nil
+	}
+
+	// 每个文件使用独立的SectionReader，同时打开的多个文件互不影响读取位置
+	return &tarFile{entry: entry, reader: io.NewSectionReader(t.file, entry.offset, entry.size)}, nil
+}
+
+// Name 实现 fs.FileInfo
+func (e *tarEntry) Name() string { return e.name }
+
+// Size 实现 fs.FileInfo
+func (e *tarEntry) Size() int64 { return e.size }
+
+// Mode 实现 fs.FileInfo
+func (e *tarEntry) Mode() fs.FileMode { return e.mode }
+
+// ModTime 实现 fs.FileInfo
+func (e *tarEntry) ModTime() time.Time { return e.modTime }
+
+// IsDir 实现 fs.FileInfo
+func (e *tarEntry) IsDir() bool { return e.mode.IsDir() }
+
+// Sys 实现 fs.FileInfo
+func (e *tarEntry) Sys() any { return nil }
+
+// tarFile tar中打开的文件
+type tarFile struct {
+	entry  *tarEntry
+	reader io.Reader
+}
+
+// Stat 实现 fs.File
+func (f *tarFile) Stat() (fs.FileInfo, error) { return f.entry, nil }
+
+// Read 实现 fs.File
+func (f *tarFile) Read(p []byte) (int, error) { return f.reader.Read(p) }
+
+// Close 实现 fs.File
+func (f *tarFile) Close() error { return nil }
+
+// tarDir tar中打开的目录
+type tarDir struct {
+	fs     *tarFS
+	path   string
+	entry  *tarEntry
+	offset int // 已经通过ReadDir返回的子项数量
+}
+
+// Stat 实现 fs.File
+func (d *tarDir) Stat() (fs.FileInfo, error) { return d.entry, nil }
+
+// Read 实现 fs.File，目录不能读取
+func (d *tarDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.path, Err: errors.New("是一个目录")}
+}
+
+// Close 实现 fs.File
+func (d *tarDir) Close() error { return nil }
+
+// ReadDir 实现 fs.ReadDirFile
+func (d *tarDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entry.children[d.offset:]
+	if n > 0 {
+		if len(remaining) == 0 {
+			return nil, io.EOF
+		}
+		remaining = remaining[:min(n, len(remaining))]
+	}
+
+	entries := make([]fs.DirEntry, 0, len(remaining))
+	for _, child := range remaining {
+		entries = append(entries, fs.FileInfoToDirEntry(d.fs.entries[path.Join(d.path, child)]))
+	}
+	d.offset += len(remaining)
+	return entries, nil
+}
+
+// countingReader 记录已读取字节数的reader，用于计算tar中文件内容的偏移
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+// Read 实现 io.Reader
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
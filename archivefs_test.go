@@ -0,0 +1,252 @@
+package githubreleasedownloader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// testArchiveLongName 超过tar头100字节限制的路径，需要PAX扩展头
+var testArchiveLongName = "tool/" + strings.Repeat("nested-directory/", 6) + "long-file-name.txt"
+
+// testArchiveFiles 测试压缩包中的文件，tool/bin 和长路径的父目录没有单独的目录条目
+var testArchiveFiles = map[string]string{
+	"tool/README.md":    "readme",
+	"tool/bin/tool":     "binary",
+	"tool/bin/helper":   "helper",
+	"tool/bin/extra":    "extra",
+	testArchiveLongName: "long",
+}
+
+// testArchiveNames 按写入顺序排列的文件名，保证生成的压缩包内容稳定
+func testArchiveNames() []string {
+	names := make([]string, 0, len(testArchiveFiles))
+	for name := range testArchiveFiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// buildTestTar 生成包含测试文件的tar，只为顶层目录写入目录条目
+func buildTestTar(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := tar.NewWriter(&buf)
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := writer.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "tool/", Mode: 0755, ModTime: modTime}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range testArchiveNames() {
+		content := testArchiveFiles[name]
+		header := &tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0644, Size: int64(len(content)), ModTime: modTime}
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// buildTestZip 生成包含测试文件的zip
+func buildTestZip(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, name := range testArchiveNames() {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := file.Write([]byte(testArchiveFiles[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// compressTestArchive 使用给定的writer压缩数据
+func compressTestArchive(t *testing.T, data []byte, newWriter func(io.Writer) (io.WriteCloser, error)) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer, err := newWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestOpenArchive(t *testing.T) {
+	tarData := buildTestTar(t)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "tool.tar", data: tarData},
+		{name: "tool.tgz", data: compressTestArchive(t, tarData, func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil })},
+		{name: "tool.tar.xz", data: compressTestArchive(t, tarData, func(w io.Writer) (io.WriteCloser, error) { return xz.NewWriter(w) })},
+		{name: "tool.tar.zst", data: compressTestArchive(t, tarData, func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) })},
+		{name: "tool.zip", data: buildTestZip(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 压缩的tar解压到临时目录，关闭后应当被删除
+			tempDir := t.TempDir()
+			t.Setenv("TMPDIR", tempDir)
+
+			filePath := filepath.Join(t.TempDir(), tt.name)
+			if err := os.WriteFile(filePath, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			archive, err := OpenArchive(filePath)
+			if err != nil {
+				t.Fatalf("OpenArchive() error = %v", err)
+			}
+
+			for name, want := range testArchiveFiles {
+				data, err := fs.ReadFile(archive, name)
+				if err != nil {
+					t.Errorf("ReadFile(%s) error = %v", name, err)
+				} else if string(data) != want {
+					t.Errorf("ReadFile(%s) = %q, want %q", name, data, want)
+				}
+			}
+
+			var walked []string
+			err = fs.WalkDir(archive, ".", func(name string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.IsDir() {
+					walked = append(walked, name)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("WalkDir() error = %v", err)
+			}
+			if !slices.Equal(walked, testArchiveNames()) {
+				t.Errorf("WalkDir() files = %v, want %v", walked, testArchiveNames())
+			}
+
+			if err := fstest.TestFS(archive, testArchiveNames()...); err != nil {
+				t.Errorf("TestFS() error = %v", err)
+			}
+
+			if err := archive.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if leftover, _ := os.ReadDir(tempDir); len(leftover) != 0 {
+				t.Errorf("Close() left temporary files: %v", leftover)
+			}
+		})
+	}
+}
+
+func TestArchiveImplicitDirs(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "tool.tar")
+	if err := os.WriteFile(filePath, buildTestTar(t), 0644); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := OpenArchive(filePath)
+	if err != nil {
+		t.Fatalf("OpenArchive() error = %v", err)
+	}
+	defer archive.Close()
+
+	// tool/bin 没有目录条目，应当由文件路径补全
+	info, err := fs.Stat(archive, "tool/bin")
+	if err != nil {
+		t.Fatalf("Stat(tool/bin) error = %v", err)
+	}
+	if !info.IsDir() {
+		t.Errorf("tool/bin is not a directory")
+	}
+	if info, err := fs.Stat(archive, "tool"); err != nil || !info.ModTime().Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Stat(tool) = %v, %v, want the directory entry's modification time", info, err)
+	}
+}
+
+func TestArchiveReadDirPaging(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "tool.tar")
+	if err := os.WriteFile(filePath, buildTestTar(t), 0644); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := OpenArchive(filePath)
+	if err != nil {
+		t.Fatalf("OpenArchive() error = %v", err)
+	}
+	defer archive.Close()
+
+	file, err := archive.Open("tool/bin")
+	if err != nil {
+		t.Fatalf("Open(tool/bin) error = %v", err)
+	}
+	defer file.Close()
+	dir := file.(fs.ReadDirFile)
+
+	var names []string
+	for {
+		entries, err := dir.ReadDir(2)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("ReadDir(2) error = %v", err)
+		}
+		if len(entries) == 0 || len(entries) > 2 {
+			t.Fatalf("ReadDir(2) returned %d entries", len(entries))
+		}
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+	}
+	if want := []string{"extra", "helper", "tool"}; !slices.Equal(names, want) {
+		t.Errorf("ReadDir pages = %v, want %v", names, want)
+	}
+
+	// 读取完毕后n<=0返回空列表而不是错误
+	if entries, err := dir.ReadDir(-1); err != nil || len(entries) != 0 {
+		t.Errorf("ReadDir(-1) after EOF = %v, %v", entries, err)
+	}
+}
+
+func TestOpenArchiveUnsupported(t *testing.T) {
+	if _, err := OpenArchive(filepath.Join(t.TempDir(), "tool.rar")); !errors.Is(err, ErrUnsupportedArchive) {
+		t.Errorf("OpenArchive() error = %v, want ErrUnsupportedArchive", err)
+	}
+}
@@ -0,0 +1,61 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimitChunkSize 限速时每次读取的最大字节数，使进度条和进度事件保持平滑
+const rateLimitChunkSize = 32 * 1024
+
+// newBandwidthLimiter 创建按字节计数的令牌桶，bytesPerSec不大于0时返回nil表示不限速
+// 桶容量为1秒的流量，且不小于单次读取的字节数
+func newBandwidthLimiter(bytesPerSec int64) *rate.Limiter {
+	if bytesPerSec <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(bytesPerSec), int(max(bytesPerSec, rateLimitChunkSize)))
+}
+
+// limitBandwidth 为响应体添加限速，同时受全局限速（WithRateLimit）和单个下载的限速（WithPerDownloadRateLimit）约束
+// 没有配置限速时直接返回原始的reader
+func (c *Client) limitBandwidth(ctx context.Context, r io.Reader) io.Reader {
+	var limiters []*rate.Limiter
+	if c.rateLimiter != nil {
+		limiters = append(limiters, c.rateLimiter)
+	}
+	if limiter := newBandwidthLimiter(c.options.PerDownloadRateLimit); limiter != nil {
+		limiters = append(limiters, limiter)
+	}
+	if len(limiters) == 0 {
+		return r
+	}
+
+	return &rateLimitedReader{ctx: ctx, reader: r, limiters: limiters}
+}
+
+// rateLimitedReader 按令牌桶限制读取速度的reader
+type rateLimitedReader struct {
+	ctx      context.Context
+	reader   io.Reader
+	limiters []*rate.Limiter
+}
+
+// Read 每次最多读取 rateLimitChunkSize 字节，读取后等待令牌
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	if len(p) > rateLimitChunkSize {
+		p = p[:rateLimitChunkSize]
+	}
+
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		for _, limiter := range r.limiters {
+			if waitErr := limiter.WaitN(r.ctx, n); waitErr != nil {
+				return n, waitErr
+			}
+		}
+	}
+	return n, err
+}
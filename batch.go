@@ -0,0 +1,136 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// ReleaseRequest 描述批量下载中的一个仓库
+type ReleaseRequest struct {
+	Owner        string // 仓库所有者
+	Repo         string // 仓库名称
+	Version      string // 版本：为空或latest表示最新版本，也可以是Tag或semver约束
+	AssetPattern string // 资产名匹配模式（glob），为空时按当前平台选择；设置后没有资产匹配时返回 ErrNoMatchingAsset，不会下载源代码
+	AutoExtract  *bool  // 是否解压，为空时使用Client的配置
+	TargetDir    string // 目标目录，为空时使用Client的配置
+}
+
+// BatchOptions 控制单次批量下载的行为
+type BatchOptions struct {
+	FailFast bool // 任一仓库失败时是否取消其余下载并返回该错误，默认尽力下载所有仓库
+}
+
+// BatchResult 表示批量下载中一个仓库的结果
+type BatchResult struct {
+	Request ReleaseRequest  // 对应的请求
+	Result  *DownloadResult // 下载结果，失败时为nil
+	Err     error           // 下载失败时的错误
+}
+
+// DownloadBatch 批量下载多个仓库的Release
+// 所有仓库的资产下载共享Client的并发限制（WithConcurrency）。默认尽力下载所有仓库，
+// 返回每个仓库的结果以及汇总的错误；opts.FailFast 为true时，任一仓库失败会取消其余下载并返回该错误
+func (c *Client) DownloadBatch(requests []ReleaseRequest, opts BatchOptions) ([]BatchResult, error) {
+	c.logger.Info("开始批量下载",
+		zap.Int("requestCount", len(requests)),
+		zap.Int("concurrency", c.options.Concurrency),
+		zap.Bool("failFast", opts.FailFast),
+	)
+
+	ctx, span := c.startSpan(context.Background(), "DownloadBatch", attrRequestCount.Int(len(requests)))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var firstErr error
+	var firstErrOnce sync.Once
+	results := make([]BatchResult, len(requests))
+	// 限制同时解析Release的仓库数量，资产下载由全局下载名额限制
+	semaphore := make(chan struct{}, max(c.options.Concurrency, 1))
+
+	for i, request := range requests {
+		wg.Add(1)
+		go func(i int, request ReleaseRequest) {
+			defer wg.Done()
+
+			results[i] = BatchResult{Request: request}
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				results[i].Err = ctx.Err()
+				c.emitDownloadResult(request.Owner, request.Repo, nil, results[i].Err)
+				return
+			}
+
+			requestCtx, requestSpan := c.startSpan(ctx, "downloadRequest", repoAttr(request.Owner, request.Repo), attrVersion.String(request.Version))
+			results[i].Result, results[i].Err = c.downloadRequest(requestCtx, request)
+			endDownloadSpan(requestSpan, results[i].Result, results[i].Err)
+			c.emitDownloadResult(request.Owner, request.Repo, results[i].Result, results[i].Err)
+
+			if results[i].Err != nil && opts.FailFast {
+				firstErrOnce.Do(func() {
+					firstErr = fmt.Errorf("%s/%s: %w", request.Owner, request.Repo, results[i].Err)
+					cancel()
+				})
+			}
+		}(i, request)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", result.Request.Owner, result.Request.Repo, result.Err))
+		}
+	}
+
+	c.logger.Info("批量下载完成",
+		zap.Int("total", len(results)),
+		zap.Int("failed", len(errs)),
+	)
+
+	err := firstErr
+	if err == nil {
+		err = errors.Join(errs...)
+	}
+	endSpan(span, err)
+	return results, err
+}
+
+// downloadRequest 下载批量请求中的一个仓库
+func (c *Client) downloadRequest(ctx context.Context, request ReleaseRequest) (*DownloadResult, error) {
+	fo := c.defaultFetchOptions()
+	fo.assetPattern = request.AssetPattern
+	if request.AutoExtract != nil {
+		fo.autoExtract = *request.AutoExtract
+	}
+	if request.TargetDir != "" {
+		if err := ensureDirExists(request.TargetDir); err != nil {
+			return nil, fmt.Errorf("创建目标目录失败: %w", err)
+		}
+		fo.targetDir = request.TargetDir
+	}
+
+	// 锁定模式下忽略版本约束，下载锁文件中记录的版本
+	if c.options.FrozenLockfile {
+		entry, err := c.findLockEntry(request.Owner, request.Repo, "")
+		if err != nil {
+			return nil, err
+		}
+		return c.downloadLocked(ctx, request.Owner, request.Repo, entry.Tag, fo)
+	}
+
+	release, err := c.resolveRelease(ctx, request.Owner, request.Repo, request.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.downloadRelease(ctx, request.Owner, request.Repo, release, fo)
+}
@@ -0,0 +1,172 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"go.uber.org/zap"
+)
+
+// breakingPattern 匹配发布说明中表示不兼容变更的单词 breaking（不区分大小写）
+// 前面是连字符时（如 NON-BREAKING）不算，后面可以跟连字符（如 BREAKING-CHANGE）
+var breakingPattern = regexp.MustCompile(`(?i)(^|[^\w-])breaking\b`)
+
+// Changelog 表示两个版本之间所有Release的发布说明
+type Changelog struct {
+	Owner   string           `json:"owner"`   // 仓库所有者
+	Repo    string           `json:"repo"`    // 仓库名称
+	From    string           `json:"from"`    // 起始版本（不包含）
+	To      string           `json:"to"`      // 目标版本（包含）
+	Entries []ChangelogEntry `json:"entries"` // 按semver从新到旧排列的Release
+}
+
+// ChangelogEntry 表示一个Release的发布说明
+type ChangelogEntry struct {
+	Tag         string    `json:"tag"`                  // Tag名称
+	Name        string    `json:"name,omitempty"`       // Release名称
+	Body        string    `json:"body"`                 // 发布说明
+	URL         string    `json:"url,omitempty"`        // Release网页地址
+	PublishedAt time.Time `json:"publishedAt,omitzero"` // 发布时间
+	Prerelease  bool      `json:"prerelease,omitempty"` // 是否为预发布版本
+	Breaking    bool      `json:"breaking"`             // 发布说明中是否有单词 breaking（不区分大小写）
+}
+
+// HasBreaking 检查是否有Release被标记为不兼容变更
+func (c *Changelog) HasBreaking() bool {
+	for _, entry := range c.Entries {
+		if entry.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Markdown 将发布说明渲染为Markdown，每个Release一个二级标题
+// markBreaking为true时在不兼容变更的Release标题后添加标记
+func (c *Changelog) Markdown(markBreaking bool) string {
+	var b strings.Builder
+	for i, entry := range c.Entries {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		b.WriteString("## ")
+		if entry.URL != "" {
+			fmt.Fprintf(&b, "[%s](%s)", entry.Tag, entry.URL)
+		} else {
+			b.WriteString(entry.Tag)
+		}
+		if entry.Name != "" && entry.Name != entry.Tag {
+			fmt.Fprintf(&b, " %s", entry.Name)
+		}
+		if !entry.PublishedAt.IsZero() {
+			fmt.Fprintf(&b, " (%s)", entry.PublishedAt.Format("2006-01-02"))
+		}
+		if markBreaking && entry.Breaking {
+			b.WriteString(" ⚠️ BREAKING")
+		}
+		b.WriteString("\n\n")
+
+		body := strings.TrimSpace(entry.Body)
+		if body == "" {
+			body = "_没有发布说明_"
+		}
+		b.WriteString(body)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Changelog 获取从fromTag（不包含）到toTag（包含）之间所有Release的发布说明，按semver从新到旧排列
+// toTag为空时使用最新版本，fromTag比toTag新时返回错误。Tag不是semver格式的Release和草稿会被忽略，
+// 预发布版本只有作为toTag时才会包含
+func (c *Client) Changelog(owner, repo, fromTag, toTag string) (*Changelog, error) {
+	ctx, span := c.startSpan(context.Background(), "Changelog", repoAttr(owner, repo), attrVersion.String(fromTag), attrTag.String(toTag))
+	changelog, err := c.changelog(ctx, owner, repo, fromTag, toTag)
+	if changelog != nil {
+		span.SetAttributes(attrTag.String(changelog.To), attrReleaseCount.Int(len(changelog.Entries)))
+	}
+	endSpan(span, err)
+	return changelog, err
+}
+
+// changelog 获取两个版本之间的发布说明
+func (c *Client) changelog(ctx context.Context, owner, repo, fromTag, toTag string) (*Changelog, error) {
+	if toTag == "" {
+		latestTag, err := c.getLatestTagName(ctx, owner, repo)
+		if err != nil {
+			return nil, err
+		}
+		toTag = latestTag
+	}
+
+	from, err := semver.NewVersion(fromTag)
+	if err != nil {
+		return nil, fmt.Errorf("无效的版本 %q: %w", fromTag, err)
+	}
+	to, err := semver.NewVersion(toTag)
+	if err != nil {
+		return nil, fmt.Errorf("无效的版本 %q: %w", toTag, err)
+	}
+	if from.GreaterThan(to) {
+		return nil, fmt.Errorf("起始版本 %s 比目标版本 %s 新", fromTag, toTag)
+	}
+
+	releases, err := c.listReleases(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	type versionedEntry struct {
+		version *semver.Version
+		entry   ChangelogEntry
+	}
+
+	var entries []versionedEntry
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+		v, err := semver.NewVersion(release.TagName)
+		if err != nil || !v.GreaterThan(from) || v.GreaterThan(to) {
+			continue
+		}
+		if release.Prerelease && !v.Equal(to) {
+			continue
+		}
+
+		entries = append(entries, versionedEntry{version: v, entry: ChangelogEntry{
+			Tag:         release.TagName,
+			Name:        release.Name,
+			Body:        release.Body,
+			URL:         release.HTMLURL,
+			PublishedAt: release.PublishedAt,
+			Prerelease:  release.Prerelease,
+			Breaking:    breakingPattern.MatchString(release.Body),
+		}})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].version.GreaterThan(entries[j].version)
+	})
+
+	changelog := &Changelog{Owner: owner, Repo: repo, From: fromTag, To: toTag, Entries: make([]ChangelogEntry, 0, len(entries))}
+	for _, e := range entries {
+		changelog.Entries = append(changelog.Entries, e.entry)
+	}
+
+	c.logger.Info("获取发布说明成功",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.String("from", fromTag),
+		zap.String("to", toTag),
+		zap.Int("releaseCount", len(changelog.Entries)),
+		zap.Bool("breaking", changelog.HasBreaking()),
+	)
+	return changelog, nil
+}
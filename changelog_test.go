@@ -0,0 +1,120 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// staticProvider 返回固定Release列表的ReleaseProvider，用于测试
+type staticProvider struct {
+	releases []*Release
+}
+
+func (p *staticProvider) LatestRelease(ctx context.Context, owner, repo string) (*Release, error) {
+	for _, release := range p.releases {
+		if !release.Draft && !release.Prerelease {
+			return release, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s/%s", ErrNoRelease, owner, repo)
+}
+
+func (p *staticProvider) ReleaseByTag(ctx context.Context, owner, repo, tag string) (*Release, error) {
+	for _, release := range p.releases {
+		if release.TagName == tag {
+			return release, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrTagNotFound, tag)
+}
+
+func (p *staticProvider) ListReleases(ctx context.Context, owner, repo string) ([]*Release, error) {
+	return p.releases, nil
+}
+
+func (p *staticProvider) AssetDownloadURL(asset *Asset) string { return asset.BrowserDownloadURL }
+
+func (p *staticProvider) SourceArchiveURL(owner, repo, tag string) string { return "" }
+
+func TestBreakingPattern(t *testing.T) {
+	tests := []struct {
+		body string
+		want bool
+	}{
+		{body: "BREAKING CHANGE: the config format changed", want: true},
+		{body: "### Breaking changes\n- removed --foo", want: true},
+		{body: "- **Breaking**: dropped Go 1.20", want: true},
+		{body: "BREAKING-CHANGE: renamed flag", want: true},
+		{body: "breaking: lower case", want: true},
+		{body: "This is a non-breaking release", want: false},
+		{body: "NON-BREAKING: internal refactor", want: false},
+		{body: "Fixed a bug in the codebreaking module", want: false},
+		{body: "Icebreakings are fun", want: false},
+		{body: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := breakingPattern.MatchString(tt.body); got != tt.want {
+			t.Errorf("breakingPattern.MatchString(%q) = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestChangelog(t *testing.T) {
+	client, err := NewClient(WithCacheDir(t.TempDir()), WithProvider(&staticProvider{releases: []*Release{
+		{TagName: "v2.0.0-rc.1", Prerelease: true, Body: "release candidate"},
+		{TagName: "v1.3.0", Body: "**Breaking**: removed the old API"},
+		{TagName: "v1.2.1", Body: "non-breaking fix"},
+		{TagName: "v1.2.0", Draft: true, Body: "draft"},
+		{TagName: "nightly", Body: "not semver"},
+		{TagName: "v1.1.0", Body: "feature"},
+		{TagName: "v1.0.0", Body: "initial"},
+	}}))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	tests := []struct {
+		name         string
+		from, to     string
+		wantTags     []string
+		wantBreaking bool
+		wantErr      bool
+	}{
+		{name: "range", from: "v1.0.0", to: "v1.2.1", wantTags: []string{"v1.2.1", "v1.1.0"}},
+		{name: "latest", from: "v1.1.0", wantTags: []string{"v1.3.0", "v1.2.1"}, wantBreaking: true},
+		{name: "prerelease target", from: "v1.2.1", to: "v2.0.0-rc.1", wantTags: []string{"v2.0.0-rc.1", "v1.3.0"}, wantBreaking: true},
+		{name: "same version", from: "v1.1.0", to: "v1.1.0", wantTags: []string{}},
+		{name: "reversed", from: "v1.3.0", to: "v1.0.0", wantErr: true},
+		{name: "invalid from", from: "nightly", to: "v1.3.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changelog, err := client.Changelog("owner", "repo", tt.from, tt.to)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Changelog() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Changelog() error = %v", err)
+			}
+
+			tags := make([]string, 0, len(changelog.Entries))
+			for _, entry := range changelog.Entries {
+				tags = append(tags, entry.Tag)
+			}
+			if !slices.Equal(tags, tt.wantTags) {
+				t.Errorf("Changelog() tags = %v, want %v", tags, tt.wantTags)
+			}
+			if got := changelog.HasBreaking(); got != tt.wantBreaking {
+				t.Errorf("HasBreaking() = %v, want %v", got, tt.wantBreaking)
+			}
+		})
+	}
+}
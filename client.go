@@ -1,40 +1,49 @@
 package githubreleasedownloader
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
-	"github.com/google/go-github/v76/github"
 	"go.uber.org/zap"
-	"go.uber.org/zap/zapcore"
-	"golang.org/x/net/proxy"
+	"golang.org/x/net/http/httpproxy"
 	"golang.org/x/oauth2"
+	"golang.org/x/time/rate"
 )
 
 // Downloader 定义下载接口
 type Downloader interface {
 	// DownloadLatestRelease 下载最新版本的Release
-	DownloadLatestRelease(owner, repo string) (string, error)
+	DownloadLatestRelease(owner, repo string) (*DownloadResult, error)
 
 	// DownloadSpecificRelease 下载指定版本的Release
-	DownloadSpecificRelease(owner, repo, tag string) (string, error)
+	DownloadSpecificRelease(owner, repo, tag string) (*DownloadResult, error)
 
 	// IsLatestVersion 检查当前版本是否为最新版本
 	IsLatestVersion(owner, repo, currentVersion string) (bool, error)
 
 	// DownloadSourceCode 下载源代码
-	DownloadSourceCode(owner, repo, tag string) (string, error)
+	DownloadSourceCode(owner, repo, tag string) (*DownloadResult, error)
 }
 
 // Client 是库的主要入口点
 type Client struct {
-	httpClient   *http.Client
-	githubClient *github.Client
-	options      *Options
-	logger       *zap.Logger
+	httpClient    *http.Client
+	provider      ReleaseProvider
+	options       *Options
+	logger        *zap.Logger
+	metrics       MetricsRecorder
+	lockfile      *Lockfile     // 锁文件，未设置时为nil
+	lockMu        sync.Mutex    // 保护lockfile的并发写入
+	downloadSlots chan struct{} // 全局下载并发名额
+	tracer        Tracer        // 链路追踪，未设置时不记录Span
+	rateLimiter   *rate.Limiter // 全局带宽限制，未设置时为nil
+	eventMu       sync.Mutex    // 保证事件处理函数串行调用
 }
 
 // NewClient 创建一个新的客户端实例
@@ -48,7 +57,7 @@ func NewClient(opts ...Option) (*Client, error) {
 	}
 
 	// 设置日志
-	logger, err := setupLogger(options.LoggerLevel)
+	logger, err := setupLogger(options)
 	if err != nil {
 		return nil, fmt.Errorf("设置日志失败: %w", err)
 	}
@@ -60,8 +69,19 @@ func NewClient(opts ...Option) (*Client, error) {
 		return nil, fmt.Errorf("创建HTTP客户端失败: %w", err)
 	}
 
-	// 创建GitHub客户端
-	githubClient := createGitHubClient(httpClient, options.AccessToken)
+	// 按凭据链查找访问令牌
+	tokenSource, err := resolveTokenSource(httpClient, options, logger)
+	if err != nil {
+		logger.Error("查找访问令牌失败", zap.Error(err))
+		return nil, fmt.Errorf("查找访问令牌失败: %w", err)
+	}
+
+	// 创建Release提供者，默认使用GitHub
+	provider, err := createProvider(httpClient, options, tokenSource)
+	if err != nil {
+		logger.Error("创建Release提供者失败", zap.Error(err))
+		return nil, fmt.Errorf("创建Release提供者失败: %w", err)
+	}
 
 	// 设置缓存目录
 	if options.CacheDir == "" {
@@ -79,6 +99,16 @@ func NewClient(opts ...Option) (*Client, error) {
 		return nil, fmt.Errorf("创建缓存目录失败: %w", err)
 	}
 
+	// 设置多版本安装根目录
+	if options.InstallDir == "" {
+		installDir, err := getDefaultInstallDir()
+		if err != nil {
+			logger.Error("获取默认安装目录失败", zap.Error(err))
+			return nil, fmt.Errorf("获取默认安装目录失败: %w", err)
+		}
+		options.InstallDir = installDir
+	}
+
 	// 如果设置了目标目录，确保它存在
 	if options.TargetDir != "" && options.TargetDir != options.CacheDir {
 		if err := ensureDirExists(options.TargetDir); err != nil {
@@ -87,96 +117,118 @@ func NewClient(opts ...Option) (*Client, error) {
 		}
 	}
 
+	// 加载锁文件
+	lockfile, err := loadClientLockfile(options)
+	if err != nil {
+		logger.Error("加载锁文件失败", zap.Error(err))
+		return nil, fmt.Errorf("加载锁文件失败: %w", err)
+	}
+
 	client := &Client{
-		httpClient:   httpClient,
-		githubClient: githubClient,
-		options:      options,
-		logger:       logger,
+		httpClient:    httpClient,
+		provider:      provider,
+		options:       options,
+		logger:        logger,
+		lockfile:      lockfile,
+		downloadSlots: make(chan struct{}, max(options.Concurrency, 1)),
+		tracer:        newTracer(options),
+		metrics:       newMetricsRecorder(options),
+		rateLimiter:   newBandwidthLimiter(options.RateLimit),
 	}
 
 	logger.Info("GitHub Release Downloader 客户端已初始化",
-		zap.String("缓存目录", options.CacheDir),
-		zap.Int("并发数", options.Concurrency),
-		zap.Bool("自动解压", options.AutoExtract),
+		zap.String("cacheDir", options.CacheDir),
+		zap.Int("concurrency", options.Concurrency),
+		zap.Bool("autoExtract", options.AutoExtract),
 	)
 
 	return client, nil
 }
 
-// setupLogger 设置日志
-func setupLogger(level string) (*zap.Logger, error) {
-	var zapLevel zapcore.Level
-	switch level {
-	case "debug":
-		zapLevel = zapcore.DebugLevel
-	case "info":
-		zapLevel = zapcore.InfoLevel
-	case "warn":
-		zapLevel = zapcore.WarnLevel
-	case "error":
-		zapLevel = zapcore.ErrorLevel
-	default:
-		zapLevel = zapcore.InfoLevel
-	}
-
-	config := zap.Config{
-		Level:       zap.NewAtomicLevelAt(zapLevel),
-		Development: false,
-		Encoding:    "json",
-		EncoderConfig: zapcore.EncoderConfig{
-			TimeKey:        "time",
-			LevelKey:       "level",
-			NameKey:        "logger",
-			CallerKey:      "caller",
-			FunctionKey:    zapcore.OmitKey,
-			MessageKey:     "msg",
-			StacktraceKey:  "stacktrace",
-			LineEnding:     zapcore.DefaultLineEnding,
-			EncodeLevel:    zapcore.LowercaseLevelEncoder,
-			EncodeTime:     zapcore.ISO8601TimeEncoder,
-			EncodeDuration: zapcore.SecondsDurationEncoder,
-			EncodeCaller:   zapcore.ShortCallerEncoder,
-		},
-		OutputPaths:      []string{"stdout"},
-		ErrorOutputPaths: []string{"stderr"},
-	}
-
-	return config.Build()
-}
-
 // createHTTPClient 创建HTTP客户端
+// 所有请求（包括携带访问令牌的API请求）都使用这里创建的Transport，因此都会经过配置的代理
 func createHTTPClient(options *Options) (*http.Client, error) {
-	transport := &http.Transport{
+	proxyFunc, err := createProxyFunc(options.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var transport http.RoundTripper = &http.Transport{
+		Proxy:               proxyFunc,
 		MaxIdleConns:        100,
 		MaxIdleConnsPerHost: 20,
 	}
+	if wrapper, ok := options.Tracer.(TransportWrapper); ok {
+		transport = wrapper.WrapTransport(transport)
+	}
+	if options.Metrics != nil {
+		transport = &metricsTransport{base: transport, metrics: options.Metrics}
+	}
+
+	return &http.Client{
+		Transport:     transport,
+		Timeout:       options.Timeout,
+		CheckRedirect: checkRedirect,
+	}, nil
+}
+
+// checkRedirect 处理重定向，重定向到其他主机时移除Authorization头
+// net/http只在重定向到非子域名时移除，这里更严格，避免令牌发送给存储服务等第三方
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("重定向次数过多")
+	}
+	if req.URL.Host != via[0].URL.Host {
+		req.Header.Del("Authorization")
+	}
+	return nil
+}
+
+// createProxyFunc 创建代理选择函数
+// 未设置代理时使用环境变量 HTTP_PROXY、HTTPS_PROXY 和 NO_PROXY；设置了代理时所有请求使用该代理，
+// 但仍然遵循 NO_PROXY 规则。代理地址支持 http://、https://、socks5://（可以包含 user:pass@ 认证信息），
+// 没有协议前缀的 host:port 按SOCKS5代理处理
+func createProxyFunc(proxyURL string) (func(*http.Request) (*url.URL, error), error) {
+	config := httpproxy.FromEnvironment()
 
-	// 如果设置了代理
-	if options.ProxyURL != "" {
-		dialer, err := proxy.SOCKS5("tcp", options.ProxyURL, nil, proxy.Direct)
+	if proxyURL != "" {
+		if !strings.Contains(proxyURL, "://") {
+			proxyURL = "socks5://" + proxyURL
+		}
+		u, err := url.Parse(proxyURL)
 		if err != nil {
-			return nil, fmt.Errorf("创建SOCKS5代理失败: %w", err)
+			return nil, fmt.Errorf("无效的代理地址: %w", err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("不支持的代理协议: %s", u.Scheme)
+		}
+		if u.Host == "" {
+			return nil, fmt.Errorf("无效的代理地址: %s", u.Redacted())
 		}
-		transport.DialContext = dialer.(proxy.ContextDialer).DialContext
+		config.HTTPProxy = u.String()
+		config.HTTPSProxy = u.String()
 	}
 
-	return &http.Client{
-		Transport: transport,
-		Timeout:   options.Timeout,
+	proxyForURL := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyForURL(req.URL)
 	}, nil
 }
 
-// createGitHubClient 创建GitHub客户端
-func createGitHubClient(httpClient *http.Client, accessToken string) *github.Client {
-	if accessToken != "" {
-		ctx := context.Background()
-		ts := oauth2.StaticTokenSource(
-			&oauth2.Token{AccessToken: accessToken},
-		)
-		tc := oauth2.NewClient(ctx, ts)
-		return github.NewClient(tc)
-	}
-	return github.NewClient(httpClient)
+// createProvider 根据配置创建Release提供者
+func createProvider(httpClient *http.Client, options *Options, tokenSource oauth2.TokenSource) (ReleaseProvider, error) {
+	switch {
+	case options.Provider != nil:
+		return options.Provider, nil
+	case options.GiteaURL != "":
+		return newGiteaProvider(httpClient, options.GiteaURL, tokenSource)
+	case options.GitLabURL != "":
+		return newGitLabProvider(httpClient, options.GitLabURL, tokenSource)
+	default:
+		return newGitHubProvider(httpClient, options, tokenSource)
+	}
 }
 
 // getDefaultCacheDir 获取默认缓存目录
@@ -188,11 +240,25 @@ func getDefaultCacheDir() (string, error) {
 	return filepath.Join(homeDir, ".github-release-downloader", "cache"), nil
 }
 
+// getDefaultInstallDir 获取默认安装根目录
+func getDefaultInstallDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".github-release-downloader", "installs"), nil
+}
+
 // ensureDirExists 确保目录存在
 func ensureDirExists(dir string) error {
 	return os.MkdirAll(dir, 0755)
 }
 
+// CacheDir 获取缓存目录
+func (c *Client) CacheDir() string {
+	return c.options.CacheDir
+}
+
 // Close 关闭客户端
 func (c *Client) Close() error {
 	if c.logger != nil {
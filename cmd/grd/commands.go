@@ -0,0 +1,284 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	githubreleasedownloader "github.com/sunwu57/github-release-downloader"
+)
+
+// errOutdated 表示检查的版本不是最新版本
+var errOutdated = errors.New("版本不是最新")
+
+// usageError 表示命令参数错误
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// spec 表示 owner/repo[@tag] 形式的仓库描述
+type spec struct {
+	owner string
+	repo  string
+	tag   string
+}
+
+// parseSpec 解析 owner/repo[@tag]，owner中可以包含/（例如GitLab的子群组）
+func parseSpec(s string) (spec, error) {
+	var sp spec
+
+	path, tag, _ := strings.Cut(s, "@")
+	idx := strings.LastIndex(path, "/")
+	if idx <= 0 || idx == len(path)-1 {
+		return sp, &usageError{msg: fmt.Sprintf("无效的仓库描述 %q，格式应为 owner/repo[@tag]", s)}
+	}
+
+	sp.owner = path[:idx]
+	sp.repo = path[idx+1:]
+	sp.tag = tag
+	return sp, nil
+}
+
+// runDownload 下载Release
+func runDownload(client *githubreleasedownloader.Client, _ *flagSet, args []string) error {
+	sp, err := parseSpec(args[0])
+	if err != nil {
+		return err
+	}
+
+	var result *githubreleasedownloader.DownloadResult
+	if sp.tag == "" {
+		result, err = client.DownloadLatestRelease(sp.owner, sp.repo)
+	} else {
+		result, err = client.DownloadSpecificRelease(sp.owner, sp.repo, sp.tag)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(result.Path)
+	return nil
+}
+
+// runLatest 输出最新版本的Tag
+func runLatest(client *githubreleasedownloader.Client, _ *flagSet, args []string) error {
+	sp, err := parseSpec(args[0])
+	if err != nil {
+		return err
+	}
+
+	tag, err := client.LatestVersion(sp.owner, sp.repo)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(tag)
+	return nil
+}
+
+// runCheck 检查版本是否为最新
+func runCheck(client *githubreleasedownloader.Client, _ *flagSet, args []string) error {
+	sp, err := parseSpec(args[0])
+	if err != nil {
+		return err
+	}
+	if sp.tag == "" {
+		return &usageError{msg: "check 命令需要指定版本，格式为 owner/repo@version"}
+	}
+
+	isLatest, err := client.IsLatestVersion(sp.owner, sp.repo, sp.tag)
+	if err != nil {
+		return err
+	}
+
+	if !isLatest {
+		fmt.Printf("%s 不是最新版本\n", sp.tag)
+		return errOutdated
+	}
+
+	fmt.Printf("%s 是最新版本\n", sp.tag)
+	return nil
+}
+
+// runPlan 输出下载计划：版本、每个文件的大小、名称、最终路径和选择原因，以及预计的总字节数和最终路径
+func runPlan(client *githubreleasedownloader.Client, _ *flagSet, args []string) error {
+	sp, err := parseSpec(args[0])
+	if err != nil {
+		return err
+	}
+
+	plan, err := client.Resolve(sp.owner, sp.repo, sp.tag)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("TAG\t%s\n", plan.Tag)
+	if plan.FromCache {
+		fmt.Printf("CACHED\t%s\n", plan.Path)
+		return nil
+	}
+	for _, file := range plan.Files {
+		fmt.Printf("FILE\t%d\t%s\t%s\t%s\n", file.Size, file.Name, file.Path, file.Reason)
+	}
+	fmt.Printf("TOTAL\t%d\n", plan.TotalBytes)
+	fmt.Printf("PATH\t%s\n", plan.Path)
+	return nil
+}
+
+// runChangelog 以Markdown输出两个版本之间的发布说明，不兼容变更的版本会被标记
+func runChangelog(client *githubreleasedownloader.Client, _ *flagSet, args []string) error {
+	sp, err := parseSpec(args[0])
+	if err != nil {
+		return err
+	}
+	if sp.tag == "" {
+		return &usageError{msg: "changelog 命令需要指定起始版本，格式为 owner/repo@from [to]"}
+	}
+
+	var to string
+	if len(args) > 1 {
+		to = args[1]
+	}
+
+	changelog, err := client.Changelog(sp.owner, sp.repo, sp.tag, to)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(changelog.Markdown(true))
+	return nil
+}
+
+// runSource 下载源代码
+func runSource(client *githubreleasedownloader.Client, _ *flagSet, args []string) error {
+	sp, err := parseSpec(args[0])
+	if err != nil {
+		return err
+	}
+
+	result, err := client.DownloadSourceCode(sp.owner, sp.repo, sp.tag)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(result.Path)
+	return nil
+}
+
+// runInstall 安装并激活指定版本
+func runInstall(client *githubreleasedownloader.Client, _ *flagSet, args []string) error {
+	sp, err := parseSpec(args[0])
+	if err != nil {
+		return err
+	}
+
+	path, err := client.Install(sp.owner, sp.repo, sp.tag)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(path)
+	return nil
+}
+
+// runList 列出已安装的版本，当前激活的版本以*标记
+func runList(client *githubreleasedownloader.Client, _ *flagSet, args []string) error {
+	sp, err := parseSpec(args[0])
+	if err != nil {
+		return err
+	}
+
+	versions, err := client.ListInstalled(sp.owner, sp.repo)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range versions {
+		marker := " "
+		if v.Active {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\t%s\t%s\n", marker, v.Tag, v.InstalledAt.Format("2006-01-02 15:04:05"), v.Path)
+	}
+	return nil
+}
+
+// runCache 管理缓存目录
+func runCache(client *githubreleasedownloader.Client, flags *flagSet, args []string) error {
+	cacheDir := client.CacheDir()
+
+	switch args[0] {
+	case "dir":
+		fmt.Println(cacheDir)
+	case "list":
+		entries, err := os.ReadDir(cacheDir)
+		if err != nil {
+			return fmt.Errorf("读取缓存目录失败: %w", err)
+		}
+		for _, entry := range entries {
+			size, err := dirSize(filepath.Join(cacheDir, entry.Name()))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%d\t%s\n", size, entry.Name())
+		}
+	case "clean":
+		entries, err := os.ReadDir(cacheDir)
+		if err != nil {
+			return fmt.Errorf("读取缓存目录失败: %w", err)
+		}
+		// 缓存目录可能被设置为包含其他文件的目录，未确认时只列出将被删除的内容
+		if !flags.yes {
+			for _, entry := range entries {
+				fmt.Println(filepath.Join(cacheDir, entry.Name()))
+			}
+			return &usageError{msg: fmt.Sprintf("将删除缓存目录 %s 中的 %d 项，使用 -yes 确认", cacheDir, len(entries))}
+		}
+		for _, entry := range entries {
+			if err := os.RemoveAll(filepath.Join(cacheDir, entry.Name())); err != nil {
+				return fmt.Errorf("清理缓存失败: %w", err)
+			}
+		}
+	default:
+		return &usageError{msg: fmt.Sprintf("未知的缓存操作 %q，可选 dir、list、clean", args[0])}
+	}
+
+	return nil
+}
+
+// runSync 按清单文件同步所有工具，输出每一项的结果
+func runSync(client *githubreleasedownloader.Client, flags *flagSet, args []string) error {
+	results, err := client.SyncManifest(args[0], githubreleasedownloader.BatchOptions{FailFast: flags.failFast})
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Printf("FAIL\t%s\t%v\n", result.Repo, result.Err)
+			continue
+		}
+		fmt.Printf("OK\t%s@%s\t%s\n", result.Repo, result.Result.Tag, result.Result.Path)
+	}
+	return err
+}
+
+// dirSize 计算文件或目录的总大小
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("计算缓存大小失败: %w", err)
+	}
+	return size, nil
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	githubreleasedownloader "github.com/sunwu57/github-release-downloader"
+)
+
+// flagSet 包含所有命令共用的客户端选项
+type flagSet struct {
+	*flag.FlagSet
+
+	concurrency    int
+	bufferSize     int
+	cacheDir       string
+	timeout        time.Duration
+	proxyURL       string
+	autoExtract    bool
+	targetDir      string
+	downloadSource bool
+	checkLatest    bool
+	loggerLevel    string
+	accessToken    string
+	showProgress   bool
+	installDir     string
+	smokeTest      bool
+	skipChecksum   bool
+	lockfile       string
+	frozen         bool
+	failFast       bool
+	yes            bool
+	enterpriseURL  string
+	uploadURL      string
+	giteaURL       string
+	gitlabURL      string
+	appID          int64
+	installationID int64
+	appKeyFile     string
+	privateRepos   stringList
+	mirrors        mirrorList
+	mirrorProbe    bool
+	rateLimit      int64
+	perDownload    int64
+}
+
+// newFlagSet 创建命令的选项集合，选项默认值取自 GRD_ 前缀的环境变量
+func newFlagSet(name string) *flagSet {
+	f := &flagSet{FlagSet: flag.NewFlagSet(name, flag.ContinueOnError)}
+
+	f.IntVar(&f.concurrency, "concurrency", envInt("GRD_CONCURRENCY", githubreleasedownloader.DefaultConcurrency), "并发下载数量 [GRD_CONCURRENCY]")
+	f.IntVar(&f.bufferSize, "buffer-size", envInt("GRD_BUFFER_SIZE", githubreleasedownloader.DefaultBufferSize), "缓冲区大小（字节） [GRD_BUFFER_SIZE]")
+	f.StringVar(&f.cacheDir, "cache-dir", os.Getenv("GRD_CACHE_DIR"), "缓存目录 [GRD_CACHE_DIR]")
+	f.DurationVar(&f.timeout, "timeout", envDuration("GRD_TIMEOUT", githubreleasedownloader.DefaultTimeout), "下载超时 [GRD_TIMEOUT]")
+	f.StringVar(&f.proxyURL, "proxy", os.Getenv("GRD_PROXY"), "代理地址: http://、https://、socks5://[user:pass@]host:port [GRD_PROXY]")
+	f.BoolVar(&f.autoExtract, "extract", envBool("GRD_EXTRACT", false), "自动解压 [GRD_EXTRACT]")
+	f.StringVar(&f.targetDir, "target-dir", os.Getenv("GRD_TARGET_DIR"), "目标目录 [GRD_TARGET_DIR]")
+	f.BoolVar(&f.downloadSource, "download-source", envBool("GRD_DOWNLOAD_SOURCE", true), "没有匹配的资产时下载源码 [GRD_DOWNLOAD_SOURCE]")
+	f.BoolVar(&f.checkLatest, "check-latest", envBool("GRD_CHECK_LATEST", true), "已下载最新版本时跳过下载 [GRD_CHECK_LATEST]")
+	f.StringVar(&f.loggerLevel, "log-level", envString("GRD_LOG_LEVEL", "error"), "日志级别: debug、info、warn、error [GRD_LOG_LEVEL]")
+	f.StringVar(&f.accessToken, "token", os.Getenv("GRD_TOKEN"), "访问令牌，未设置时依次查找GITHUB_TOKEN/GH_TOKEN、gh配置和netrc [GRD_TOKEN]")
+	f.BoolVar(&f.showProgress, "progress", envBool("GRD_PROGRESS", false), "显示下载进度条 [GRD_PROGRESS]")
+	f.StringVar(&f.installDir, "install-dir", os.Getenv("GRD_INSTALL_DIR"), "多版本安装根目录 [GRD_INSTALL_DIR]")
+	f.BoolVar(&f.smokeTest, "smoke-test", envBool("GRD_SMOKE_TEST", false), "自更新时以--version试运行新程序 [GRD_SMOKE_TEST]")
+	f.BoolVar(&f.skipChecksum, "skip-checksum", envBool("GRD_SKIP_CHECKSUM", false), "自更新时Release没有校验和文件则跳过校验 [GRD_SKIP_CHECKSUM]")
+	f.StringVar(&f.lockfile, "lockfile", os.Getenv("GRD_LOCKFILE"), "锁文件路径 [GRD_LOCKFILE]")
+	f.BoolVar(&f.frozen, "frozen", envBool("GRD_FROZEN", false), "只下载锁文件中记录的文件 [GRD_FROZEN]")
+	f.BoolVar(&f.failFast, "fail-fast", envBool("GRD_FAIL_FAST", false), "批量下载时第一个失败后取消其余下载 [GRD_FAIL_FAST]")
+	f.BoolVar(&f.yes, "yes", envBool("GRD_YES", false), "确认执行会删除文件的操作（cache clean） [GRD_YES]")
+	f.StringVar(&f.enterpriseURL, "enterprise-url", os.Getenv("GRD_ENTERPRISE_URL"), "GitHub Enterprise Server API地址 [GRD_ENTERPRISE_URL]")
+	f.StringVar(&f.uploadURL, "enterprise-upload-url", os.Getenv("GRD_ENTERPRISE_UPLOAD_URL"), "GitHub Enterprise Server上传地址 [GRD_ENTERPRISE_UPLOAD_URL]")
+	f.StringVar(&f.giteaURL, "gitea-url", os.Getenv("GRD_GITEA_URL"), "Gitea/Forgejo实例地址，设置后从该实例获取Release [GRD_GITEA_URL]")
+	f.StringVar(&f.gitlabURL, "gitlab-url", os.Getenv("GRD_GITLAB_URL"), "GitLab实例地址，设置后从该实例获取Release [GRD_GITLAB_URL]")
+	f.Int64Var(&f.appID, "app-id", envInt64("GRD_APP_ID", 0), "GitHub App ID，设置后以App身份访问GitHub [GRD_APP_ID]")
+	f.Int64Var(&f.installationID, "app-installation-id", envInt64("GRD_APP_INSTALLATION_ID", 0), "GitHub App安装ID [GRD_APP_INSTALLATION_ID]")
+	f.StringVar(&f.appKeyFile, "app-private-key", os.Getenv("GRD_APP_PRIVATE_KEY"), "GitHub App私钥文件（PEM） [GRD_APP_PRIVATE_KEY]")
+	f.privateRepos = parseStringList(os.Getenv("GRD_PRIVATE_REPOS"))
+	f.Var(&f.privateRepos, "private-repo", "私有的GitHub仓库（owner/repo），直接通过API下载，可以重复指定，环境变量以逗号分隔 [GRD_PRIVATE_REPOS]")
+	f.mirrors = parseMirrorList(os.Getenv("GRD_MIRRORS"))
+	f.Var(&f.mirrors, "mirror", "下载镜像，可以重复指定: 前缀（如 https://ghproxy.net/）或 源=目标（主机名或URL前缀），环境变量以逗号分隔 [GRD_MIRRORS]")
+	f.BoolVar(&f.mirrorProbe, "mirror-probe", envBool("GRD_MIRROR_PROBE", false), "下载前探测镜像并优先使用最快的镜像 [GRD_MIRROR_PROBE]")
+	f.Int64Var(&f.rateLimit, "rate-limit", envInt64("GRD_RATE_LIMIT", 0), "所有下载共享的带宽上限（字节/秒），0表示不限速 [GRD_RATE_LIMIT]")
+	f.Int64Var(&f.perDownload, "per-download-rate-limit", envInt64("GRD_PER_DOWNLOAD_RATE_LIMIT", 0), "单个下载的带宽上限（字节/秒），0表示不限速 [GRD_PER_DOWNLOAD_RATE_LIMIT]")
+
+	return f
+}
+
+// options 将选项转换为客户端配置
+func (f *flagSet) options() ([]githubreleasedownloader.Option, error) {
+	options := []githubreleasedownloader.Option{
+		githubreleasedownloader.WithConcurrency(f.concurrency),
+		githubreleasedownloader.WithBufferSize(f.bufferSize),
+		githubreleasedownloader.WithCacheDir(f.cacheDir),
+		githubreleasedownloader.WithTimeout(f.timeout),
+		githubreleasedownloader.WithProxyURL(f.proxyURL),
+		githubreleasedownloader.WithAutoExtract(f.autoExtract),
+		githubreleasedownloader.WithTargetDir(f.targetDir),
+		githubreleasedownloader.WithDownloadSource(f.downloadSource),
+		githubreleasedownloader.WithCheckLatest(f.checkLatest),
+		githubreleasedownloader.WithLoggerLevel(f.loggerLevel),
+		githubreleasedownloader.WithAccessToken(f.accessToken),
+		githubreleasedownloader.WithShowProgress(f.showProgress),
+		githubreleasedownloader.WithInstallDir(f.installDir),
+		githubreleasedownloader.WithSelfUpdateSmokeTest(f.smokeTest),
+		githubreleasedownloader.WithSelfUpdateSkipChecksum(f.skipChecksum),
+		githubreleasedownloader.WithLockfile(f.lockfile),
+		githubreleasedownloader.WithFrozenLockfile(f.frozen),
+		githubreleasedownloader.WithEnterpriseURL(f.enterpriseURL, f.uploadURL),
+		githubreleasedownloader.WithPrivateRepos(f.privateRepos...),
+		githubreleasedownloader.WithGiteaURL(f.giteaURL),
+		githubreleasedownloader.WithGitLabURL(f.gitlabURL),
+		githubreleasedownloader.WithMirrors(f.mirrors...),
+		githubreleasedownloader.WithMirrorProbe(f.mirrorProbe),
+		githubreleasedownloader.WithRateLimit(f.rateLimit),
+		githubreleasedownloader.WithPerDownloadRateLimit(f.perDownload),
+	}
+
+	if f.appID != 0 {
+		key, err := os.ReadFile(f.appKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取GitHub App私钥失败: %w", err)
+		}
+		options = append(options, githubreleasedownloader.WithGitHubApp(f.appID, f.installationID, key))
+	}
+
+	return options, nil
+}
+
+// stringList 可以重复指定的字符串选项
+type stringList []string
+
+// String 实现flag.Value接口
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set 实现flag.Value接口，追加一个值
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// parseStringList 解析以逗号分隔的值
+func parseStringList(value string) stringList {
+	var list stringList
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
+
+// mirrorList 可以重复指定的镜像选项
+type mirrorList []githubreleasedownloader.Mirror
+
+// String 实现flag.Value接口
+func (m *mirrorList) String() string {
+	var parts []string
+	for _, mirror := range *m {
+		if mirror.From == "" {
+			parts = append(parts, mirror.To)
+		} else {
+			parts = append(parts, mirror.From+"="+mirror.To)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set 实现flag.Value接口，追加一条镜像规则
+func (m *mirrorList) Set(value string) error {
+	*m = append(*m, parseMirror(value))
+	return nil
+}
+
+// parseMirror 解析镜像规则：没有 = 时为前缀镜像，否则为 源=目标
+func parseMirror(value string) githubreleasedownloader.Mirror {
+	from, to, ok := strings.Cut(value, "=")
+	if !ok {
+		return githubreleasedownloader.Mirror{To: value}
+	}
+	return githubreleasedownloader.Mirror{From: from, To: to}
+}
+
+// parseMirrorList 解析以逗号分隔的镜像规则
+func parseMirrorList(value string) mirrorList {
+	var mirrors mirrorList
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			mirrors = append(mirrors, parseMirror(part))
+		}
+	}
+	return mirrors
+}
+
+// envString 读取字符串环境变量
+func envString(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return def
+}
+
+// envInt 读取整数环境变量，无法解析时使用默认值
+func envInt(key string, def int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return def
+}
+
+// envInt64 读取64位整数环境变量，无法解析时使用默认值
+func envInt64(key string, def int64) int64 {
+	if v, err := strconv.ParseInt(os.Getenv(key), 10, 64); err == nil {
+		return v
+	}
+	return def
+}
+
+// envBool 读取布尔环境变量，无法解析时使用默认值
+func envBool(key string, def bool) bool {
+	if v, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return v
+	}
+	return def
+}
+
+// envDuration 读取时间间隔环境变量，无法解析时使用默认值
+func envDuration(key string, def time.Duration) time.Duration {
+	if v, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return v
+	}
+	return def
+}
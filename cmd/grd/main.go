@@ -0,0 +1,146 @@
+// grd 是基于 github-release-downloader 的命令行工具
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	githubreleasedownloader "github.com/sunwu57/github-release-downloader"
+)
+
+// 退出码
+const (
+	exitOK               = 0
+	exitError            = 1
+	exitUsage            = 2
+	exitNoRelease        = 3
+	exitNoMatchingAsset  = 4
+	exitRateLimited      = 5
+	exitNetworkError     = 6
+	exitChecksumMismatch = 7
+	exitOutdated         = 10
+)
+
+const usage = `用法: grd <命令> [选项] <参数>
+
+命令:
+  download <owner/repo[@tag]>    下载Release（不指定tag时下载最新版本）
+  latest   <owner/repo>          输出最新版本的Tag
+  check    <owner/repo@version>  检查版本是否为最新（不是最新时退出码为 10）
+  plan     <owner/repo[@spec]>   输出下载计划而不下载（spec可以是Tag或semver约束）
+  changelog <owner/repo@from> [to]
+                                 以Markdown输出两个版本之间的发布说明（不指定to时到最新版本）
+  source   <owner/repo[@tag]>    下载源代码
+  install  <owner/repo[@tag]>    安装并激活指定版本
+  list     <owner/repo>          列出已安装的版本
+  cache    <dir|list|clean>      管理缓存目录（clean 会删除缓存目录中的所有内容，需要 -yes 确认）
+  sync     <manifest>            按清单文件（YAML/TOML）同步所有工具
+
+退出码:
+  0 成功  1 其他错误  2 用法错误  3 没有Release  4 没有匹配的资产
+  5 触发API速率限制  6 网络错误  7 SHA-256校验失败  10 版本不是最新
+
+使用 "grd <命令> -h" 查看命令选项，所有选项都可以通过 GRD_ 前缀的环境变量设置。
+`
+
+// command 表示一个子命令
+type command struct {
+	run     func(client *githubreleasedownloader.Client, flags *flagSet, args []string) error
+	minArgs int
+	maxArgs int
+}
+
+var commands = map[string]command{
+	"download":  {run: runDownload, minArgs: 1, maxArgs: 1},
+	"latest":    {run: runLatest, minArgs: 1, maxArgs: 1},
+	"check":     {run: runCheck, minArgs: 1, maxArgs: 1},
+	"plan":      {run: runPlan, minArgs: 1, maxArgs: 1},
+	"changelog": {run: runChangelog, minArgs: 1, maxArgs: 2},
+	"source":    {run: runSource, minArgs: 1, maxArgs: 1},
+	"install":   {run: runInstall, minArgs: 1, maxArgs: 1},
+	"list":      {run: runList, minArgs: 1, maxArgs: 1},
+	"cache":     {run: runCache, minArgs: 1, maxArgs: 1},
+	"sync":      {run: runSync, minArgs: 1, maxArgs: 1},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run 解析命令行并执行子命令，返回退出码
+func run(args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(os.Stderr, usage)
+		return exitUsage
+	}
+
+	name := args[0]
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "未知命令: %s\n\n%s", name, usage)
+		return exitUsage
+	}
+
+	flags := newFlagSet(name)
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	cmdArgs := flags.Args()
+	if len(cmdArgs) < cmd.minArgs || len(cmdArgs) > cmd.maxArgs {
+		fmt.Fprintf(os.Stderr, "命令 %s 参数个数错误\n\n%s", name, usage)
+		return exitUsage
+	}
+
+	options, err := flags.options()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return exitUsage
+	}
+
+	client, err := githubreleasedownloader.NewClient(options...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "创建客户端失败: %v\n", err)
+		return exitError
+	}
+	defer client.Close()
+
+	if err := cmd.run(client, flags, cmdArgs); err != nil {
+		var usageErr *usageError
+		if errors.As(err, &usageErr) {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return exitUsage
+		}
+		if errors.Is(err, errOutdated) {
+			return exitOutdated
+		}
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		return exitCode(err)
+	}
+
+	return exitOK
+}
+
+// exitCode 根据错误类型确定退出码
+func exitCode(err error) int {
+	var rateLimitErr *githubreleasedownloader.RateLimitError
+	var checksumErr *githubreleasedownloader.ChecksumMismatchError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, githubreleasedownloader.ErrNoRelease):
+		return exitNoRelease
+	case errors.Is(err, githubreleasedownloader.ErrNoMatchingAsset):
+		return exitNoMatchingAsset
+	case errors.As(err, &rateLimitErr):
+		return exitRateLimited
+	case errors.As(err, &checksumErr):
+		return exitChecksumMismatch
+	case errors.As(err, &netErr):
+		return exitNetworkError
+	default:
+		return exitError
+	}
+}
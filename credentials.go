@@ -0,0 +1,301 @@
+package githubreleasedownloader
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultGitHubHost github.com的主机名，用于查找凭据
+const defaultGitHubHost = "github.com"
+
+// credentialTarget 描述需要查找凭据的平台
+type credentialTarget struct {
+	host   string // 平台主机名，凭据按主机区分
+	github bool   // 是否为GitHub（包括GitHub Enterprise Server）
+}
+
+// resolveTokenSource 按凭据链查找访问令牌，没有找到时返回nil
+// 查找顺序：WithAccessToken、WithGitHubApp、环境变量（GITHUB_TOKEN/GH_TOKEN，企业版为GH_ENTERPRISE_TOKEN/GITHUB_ENTERPRISE_TOKEN）、
+// gh命令行工具的hosts.yml、~/.netrc、WithTokenSource。环境变量和hosts.yml只用于GitHub，
+// 所有来源都按主机区分，避免将github.com的令牌发送给企业版或其他平台
+func resolveTokenSource(httpClient *http.Client, options *Options, logger *zap.Logger) (oauth2.TokenSource, error) {
+	// 自定义的Release提供者自行处理认证
+	if options.Provider != nil {
+		return nil, nil
+	}
+
+	target, err := credentialTargetOf(options)
+	if err != nil {
+		return nil, err
+	}
+
+	logFound := func(source, token string) {
+		logger.Info("使用访问令牌",
+			zap.String("host", target.host),
+			zap.String("source", source),
+			zap.String("token", redactToken(token)),
+		)
+	}
+
+	if options.AccessToken != "" {
+		logFound("WithAccessToken", options.AccessToken)
+		return staticTokenSource(options.AccessToken), nil
+	}
+
+	if target.github && options.GitHubAppID != 0 {
+		ts, err := newGitHubAppTokenSource(httpClient, options)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("使用访问令牌",
+			zap.String("host", target.host),
+			zap.String("source", "WithGitHubApp"),
+			zap.Int64("appID", options.GitHubAppID),
+			zap.Int64("installationID", options.GitHubAppInstallationID),
+		)
+		return ts, nil
+	}
+
+	if target.github {
+		for _, key := range githubTokenEnvKeys(target.host) {
+			if token := os.Getenv(key); token != "" {
+				logFound("环境变量 "+key, token)
+				return staticTokenSource(token), nil
+			}
+		}
+
+		path, token, err := ghHostsToken(target.host)
+		if err != nil {
+			logger.Warn("读取gh配置失败", zap.String("path", path), zap.Error(err))
+		} else if token != "" {
+			logFound(path, token)
+			return staticTokenSource(token), nil
+		}
+	}
+
+	hosts := []string{target.host}
+	if target.host == defaultGitHubHost {
+		hosts = append(hosts, "api.github.com")
+	}
+	path, token, err := netrcToken(hosts)
+	if err != nil {
+		logger.Warn("读取netrc失败", zap.String("path", path), zap.Error(err))
+	} else if token != "" {
+		logFound(path, token)
+		return staticTokenSource(token), nil
+	}
+
+	if options.TokenSource != nil {
+		logger.Info("使用访问令牌",
+			zap.String("host", target.host),
+			zap.String("source", "WithTokenSource"),
+		)
+		return oauth2.ReuseTokenSource(nil, options.TokenSource), nil
+	}
+
+	logger.Debug("没有找到访问令牌，使用匿名访问", zap.String("host", target.host))
+	return nil, nil
+}
+
+// credentialTargetOf 根据配置确定需要查找凭据的平台
+func credentialTargetOf(options *Options) (credentialTarget, error) {
+	var rawURL string
+	github := false
+	switch {
+	case options.GiteaURL != "":
+		rawURL = options.GiteaURL
+	case options.GitLabURL != "":
+		rawURL = options.GitLabURL
+	case options.EnterpriseBaseURL != "":
+		rawURL = options.EnterpriseBaseURL
+		github = true
+	default:
+		return credentialTarget{host: defaultGitHubHost, github: true}, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return credentialTarget{}, fmt.Errorf("无效的平台地址: %q", rawURL)
+	}
+	return credentialTarget{host: u.Hostname(), github: github}, nil
+}
+
+// githubTokenEnvKeys 返回保存GitHub访问令牌的环境变量，与gh命令行工具的约定一致
+func githubTokenEnvKeys(host string) []string {
+	if host == defaultGitHubHost {
+		return []string{"GITHUB_TOKEN", "GH_TOKEN"}
+	}
+	return []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+}
+
+// ghHostsToken 从gh命令行工具的hosts.yml读取指定主机的令牌，文件不存在时返回空令牌
+// 令牌保存在系统密钥环中时hosts.yml里没有oauth_token，同样返回空令牌
+func ghHostsToken(host string) (string, string, error) {
+	path := ghHostsPath()
+	if path == "" {
+		return "", "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return path, "", nil
+		}
+		return path, "", err
+	}
+
+	var hosts map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return path, "", fmt.Errorf("解析hosts.yml失败: %w", err)
+	}
+
+	return path, hosts[host].OAuthToken, nil
+}
+
+// ghHostsPath 获取gh命令行工具hosts.yml的路径
+func ghHostsPath() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "hosts.yml")
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh", "hosts.yml")
+	}
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("AppData"); dir != "" {
+			return filepath.Join(dir, "GitHub CLI", "hosts.yml")
+		}
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".config", "gh", "hosts.yml")
+}
+
+// netrcToken 从netrc文件读取第一个匹配主机的密码作为令牌，只使用 machine 条目，忽略 default 条目
+func netrcToken(hosts []string) (string, string, error) {
+	path := netrcPath()
+	if path == "" {
+		return "", "", nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return path, "", nil
+		}
+		return path, "", err
+	}
+	defer file.Close()
+
+	passwords := make(map[string]string)
+	var machine, keyword string
+	inMacro := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// 宏定义的内容持续到空行为止，其中的内容不是netrc的关键字
+		if inMacro {
+			inMacro = len(fields) != 0
+			continue
+		}
+
+		for _, field := range fields {
+			// 关键字的值可以在下一行
+			switch keyword {
+			case "machine":
+				machine, keyword = field, ""
+				continue
+			case "password":
+				if machine != "" {
+					passwords[machine] = field
+				}
+				keyword = ""
+				continue
+			}
+
+			switch field {
+			case "machine", "password":
+				keyword = field
+			case "default":
+				// default条目适用于任意主机，不能把它的密码作为令牌发送给GitHub
+				machine = ""
+			case "macdef":
+				inMacro = true
+			}
+			if inMacro {
+				break
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return path, "", err
+	}
+
+	return path, lookupNetrc(passwords, hosts), nil
+}
+
+// lookupNetrc 按主机顺序查找netrc中的密码
+func lookupNetrc(passwords map[string]string, hosts []string) string {
+	for _, host := range hosts {
+		if password, ok := passwords[host]; ok {
+			return password
+		}
+	}
+	return ""
+}
+
+// netrcPath 获取netrc文件路径，可以通过NETRC环境变量指定
+func netrcPath() string {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(homeDir, "_netrc")
+	}
+	return filepath.Join(homeDir, ".netrc")
+}
+
+// staticTokenSource 创建固定令牌的TokenSource
+func staticTokenSource(token string) oauth2.TokenSource {
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+}
+
+// redactToken 隐藏令牌内容，只保留前4个字符用于辨认令牌类型
+func redactToken(token string) string {
+	if len(token) <= 8 {
+		return "****"
+	}
+	return token[:4] + "****"
+}
+
+// bearerToken 从TokenSource获取令牌，ts为nil时返回空字符串
+func bearerToken(ts oauth2.TokenSource) (string, error) {
+	if ts == nil {
+		return "", nil
+	}
+	token, err := ts.Token()
+	if err != nil {
+		return "", fmt.Errorf("获取访问令牌失败: %w", err)
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,132 @@
+package githubreleasedownloader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// writeTestNetrc 写入临时的netrc文件并通过NETRC环境变量指定
+func writeTestNetrc(t *testing.T, content string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".netrc")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NETRC", path)
+}
+
+func TestNetrcToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		netrc string
+		hosts []string
+		want  string
+	}{
+		{
+			name:  "machine",
+			netrc: "machine example.com login user password other\nmachine github.com login user password secret\n",
+			hosts: []string{"github.com"},
+			want:  "secret",
+		},
+		{
+			name:  "multi-line entry",
+			netrc: "machine github.com\n\tlogin user\n\tpassword\n\tsecret\n",
+			hosts: []string{"github.com"},
+			want:  "secret",
+		},
+		{
+			name:  "default is ignored",
+			netrc: "machine example.com password other\ndefault login anonymous password guest\n",
+			hosts: []string{"github.com"},
+		},
+		{
+			name:  "default does not override later machine",
+			netrc: "default password guest\nmachine github.com password secret\n",
+			hosts: []string{"github.com"},
+			want:  "secret",
+		},
+		{
+			name:  "macdef body is skipped",
+			netrc: "macdef init\nmachine github.com password macro\n\nmachine github.com password secret\n",
+			hosts: []string{"github.com"},
+			want:  "secret",
+		},
+		{
+			name:  "host order",
+			netrc: "machine api.github.com password api\nmachine github.com password secret\n",
+			hosts: []string{"github.com", "api.github.com"},
+			want:  "secret",
+		},
+		{
+			name:  "fallback host",
+			netrc: "machine api.github.com password api\n",
+			hosts: []string{"github.com", "api.github.com"},
+			want:  "api",
+		},
+		{
+			name:  "no match",
+			netrc: "machine example.com password other\n",
+			hosts: []string{"github.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeTestNetrc(t, tt.netrc)
+
+			_, got, err := netrcToken(tt.hosts)
+			if err != nil {
+				t.Fatalf("netrcToken() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("netrcToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNetrcTokenMissingFile(t *testing.T) {
+	t.Setenv("NETRC", filepath.Join(t.TempDir(), "missing"))
+
+	if _, token, err := netrcToken([]string{"github.com"}); err != nil || token != "" {
+		t.Errorf("netrcToken() = %q, %v, want empty token and no error", token, err)
+	}
+}
+
+func TestResolveTokenSourceNetrc(t *testing.T) {
+	for _, key := range []string{"GITHUB_TOKEN", "GH_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	writeTestNetrc(t, "machine api.github.com password api\nmachine ghe.example.com password enterprise\n")
+
+	tests := []struct {
+		name    string
+		options Options
+		want    string
+	}{
+		{name: "github.com falls back to api.github.com", want: "api"},
+		{name: "enterprise", options: Options{EnterpriseBaseURL: "https://ghe.example.com/api/v3/"}, want: "enterprise"},
+		{name: "gitea does not use api.github.com", options: Options{GiteaURL: "https://gitea.example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, err := resolveTokenSource(nil, &tt.options, zap.NewNop())
+			if err != nil {
+				t.Fatalf("resolveTokenSource() error = %v", err)
+			}
+			got, err := bearerToken(ts)
+			if err != nil {
+				t.Fatalf("bearerToken() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("token = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package githubreleasedownloader
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,7 +12,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/google/go-github/v76/github"
 	"github.com/schollz/progressbar/v3"
 	"go.uber.org/zap"
 )
@@ -22,169 +22,131 @@ type downloadResult struct {
 	err      error
 }
 
+// fetchOptions 单次下载的配置，默认取自Client的全局选项
+type fetchOptions struct {
+	assetPattern string // 资产名匹配模式（glob），为空时按当前平台选择
+	autoExtract  bool   // 是否自动解压
+	targetDir    string // 目标目录
+}
+
+// defaultFetchOptions 使用Client的全局选项构建下载配置
+func (c *Client) defaultFetchOptions() fetchOptions {
+	return fetchOptions{
+		autoExtract: c.options.AutoExtract,
+		targetDir:   c.options.TargetDir,
+	}
+}
+
 // DownloadLatestRelease 下载最新版本的Release
-func (c *Client) DownloadLatestRelease(owner, repo string) (string, error) {
+func (c *Client) DownloadLatestRelease(owner, repo string) (*DownloadResult, error) {
+	ctx, span := c.startSpan(context.Background(), "DownloadLatestRelease", repoAttr(owner, repo))
+	result, err := c.downloadLatestRelease(ctx, owner, repo)
+	endDownloadSpan(span, result, err)
+	c.emitDownloadResult(owner, repo, result, err)
+	return result, err
+}
+
+// downloadLatestRelease 下载最新版本的Release，已下载最新版本时直接返回缓存的结果
+func (c *Client) downloadLatestRelease(ctx context.Context, owner, repo string) (*DownloadResult, error) {
 	c.logger.Info("开始下载最新Release",
 		zap.String("owner", owner),
 		zap.String("repo", repo),
 	)
 
+	// 锁定模式下直接下载锁文件中记录的文件
+	if c.options.FrozenLockfile {
+		return c.downloadLocked(ctx, owner, repo, "", c.defaultFetchOptions())
+	}
+
 	// 获取最新Release
-	release, err := c.getLatestRelease(owner, repo)
+	release, err := c.getLatestRelease(ctx, owner, repo)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// 检查是否需要下载
 	if c.options.CheckLatest {
-		// 获取缓存的版本信息
-		cacheVersionPath := filepath.Join(c.options.CacheDir, fmt.Sprintf("%s-%s-version.txt", owner, repo))
-		if _, statErr := os.Stat(cacheVersionPath); statErr == nil {
-			// 读取缓存的版本
-			cachedVersion, readErr := os.ReadFile(cacheVersionPath)
-			if readErr == nil && string(cachedVersion) == release.GetTagName() {
-				c.logger.Info("当前已是最新版本，无需下载",
-					zap.String("owner", owner),
-					zap.String("repo", repo),
-					zap.String("version", release.GetTagName()),
-				)
-				return filepath.Join(c.options.CacheDir, fmt.Sprintf("%s-%s", owner, repo)), nil
-			}
-		}
-	}
-
-	// 获取Release资产
-	assets := c.getReleaseAssets(release)
-
-	// 如果没有资产且配置了下载源代码
-	if c.shouldDownloadSource(assets) {
-		c.logger.Info("没有找到Release资产，开始下载源代码",
-			zap.String("owner", owner),
-			zap.String("repo", repo),
-			zap.String("tag", release.GetTagName()),
-		)
-		return c.DownloadSourceCode(owner, repo, release.GetTagName())
-	}
-
-	// 下载资产
-	filePaths, err := c.downloadAssets(assets)
-	if err != nil {
-		return "", err
-	}
-
-	// 如果只有一个文件，直接返回
-	if len(filePaths) == 1 {
-		// 如果配置了自动解压，解压文件
-		if c.options.AutoExtract {
-			extractedPath, err := c.extractFile(filePaths[0])
-			if err != nil {
-				c.logger.Warn("解压文件失败",
-					zap.String("filePath", filePaths[0]),
-					zap.Error(err),
-				)
-				// 解压失败不影响返回
-			} else {
-				filePaths[0] = extractedPath
-			}
-		}
-
-		// 如果配置了目标目录，移动文件
-		if c.options.TargetDir != "" && c.options.TargetDir != c.options.CacheDir {
-			targetPath := filepath.Join(c.options.TargetDir, filepath.Base(filePaths[0]))
-			if err := c.moveFile(filePaths[0], targetPath); err != nil {
-				c.logger.Warn("移动文件失败",
-					zap.String("source", filePaths[0]),
-					zap.String("target", targetPath),
-					zap.Error(err),
-				)
-				// 移动失败不影响返回
-			} else {
-				filePaths[0] = targetPath
-			}
-		}
-
-		// 更新缓存的版本信息
-		if c.options.CheckLatest {
-			cacheVersionPath := filepath.Join(c.options.CacheDir, fmt.Sprintf("%s-%s-version.txt", owner, repo))
-			if err := os.WriteFile(cacheVersionPath, []byte(release.GetTagName()), 0644); err != nil {
-				c.logger.Warn("更新缓存版本信息失败",
-					zap.String("path", cacheVersionPath),
-					zap.Error(err),
-				)
-			}
-		}
-
-		return filePaths[0], nil
-	}
-
-	// 如果有多个文件，返回目录
-	dirPath := filepath.Join(c.options.CacheDir, fmt.Sprintf("%s-%s-%s", owner, repo, release.GetTagName()))
-	if err := os.MkdirAll(dirPath, 0755); err != nil {
-		return "", fmt.Errorf("创建目录失败: %w", err)
-	}
-
-	// 移动所有文件到目录
-	for _, filePath := range filePaths {
-		targetPath := filepath.Join(dirPath, filepath.Base(filePath))
-		if err := c.moveFile(filePath, targetPath); err != nil {
-			c.logger.Warn("移动文件失败",
-				zap.String("source", filePath),
-				zap.String("target", targetPath),
-				zap.Error(err),
+		cached := c.loadCachedResult(owner, repo, release.TagName)
+		c.metrics.ObserveCache(cached != nil)
+		if cached != nil {
+			c.logger.Info("当前已是最新版本，无需下载",
+				zap.String("owner", owner),
+				zap.String("repo", repo),
+				zap.String("version", release.TagName),
+				zap.String("path", cached.Path),
 			)
-			continue
-		}
 
-		// 如果配置了自动解压，解压文件
-		if c.options.AutoExtract {
-			c.extractFile(targetPath)
+			// 记录到锁文件
+			if c.lockfile != nil {
+				if err := c.lockCachedResult(cached, release); err != nil {
+					return nil, err
+				}
+			}
+			return cached, nil
 		}
 	}
 
-	// 如果配置了目标目录，移动目录
-	if c.options.TargetDir != "" && c.options.TargetDir != c.options.CacheDir {
-		targetDirPath := filepath.Join(c.options.TargetDir, filepath.Base(dirPath))
-		if err := c.moveFile(dirPath, targetDirPath); err != nil {
-			c.logger.Warn("移动目录失败",
-				zap.String("source", dirPath),
-				zap.String("target", targetDirPath),
-				zap.Error(err),
-			)
-		} else {
-			dirPath = targetDirPath
-		}
+	result, err := c.downloadRelease(ctx, owner, repo, release, c.defaultFetchOptions())
+	if err != nil {
+		return nil, err
 	}
 
-	// 更新缓存的版本信息
+	// 更新缓存的下载结果
 	if c.options.CheckLatest {
-		cacheVersionPath := filepath.Join(c.options.CacheDir, fmt.Sprintf("%s-%s-version.txt", owner, repo))
-		if err := os.WriteFile(cacheVersionPath, []byte(release.GetTagName()), 0644); err != nil {
+		if err := c.saveCachedResult(result); err != nil {
 			c.logger.Warn("更新缓存版本信息失败",
-				zap.String("path", cacheVersionPath),
+				zap.String("path", c.resultCachePath(owner, repo)),
 				zap.Error(err),
 			)
 		}
 	}
 
-	return dirPath, nil
+	return result, nil
 }
 
 // DownloadSpecificRelease 下载指定版本的Release
-func (c *Client) DownloadSpecificRelease(owner, repo, tag string) (string, error) {
+func (c *Client) DownloadSpecificRelease(owner, repo, tag string) (*DownloadResult, error) {
+	ctx, span := c.startSpan(context.Background(), "DownloadSpecificRelease", repoAttr(owner, repo), attrTag.String(tag))
+	result, err := c.downloadSpecificRelease(ctx, owner, repo, tag)
+	endDownloadSpan(span, result, err)
+	c.emitDownloadResult(owner, repo, result, err)
+	return result, err
+}
+
+// downloadSpecificRelease 下载指定版本的Release
+func (c *Client) downloadSpecificRelease(ctx context.Context, owner, repo, tag string) (*DownloadResult, error) {
 	c.logger.Info("开始下载指定版本Release",
 		zap.String("owner", owner),
 		zap.String("repo", repo),
 		zap.String("tag", tag),
 	)
 
+	// 锁定模式下直接下载锁文件中记录的文件
+	if c.options.FrozenLockfile {
+		return c.downloadLocked(ctx, owner, repo, tag, c.defaultFetchOptions())
+	}
+
 	// 获取指定版本的Release
-	release, err := c.getReleaseByTag(owner, repo, tag)
+	release, err := c.getReleaseByTag(ctx, owner, repo, tag)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
+	return c.downloadRelease(ctx, owner, repo, release, c.defaultFetchOptions())
+}
+
+// downloadRelease 下载Release的资产，按配置解压并移动到目标目录
+// 只有一个文件时结果路径为该文件，有多个文件时为包含所有文件的目录
+func (c *Client) downloadRelease(ctx context.Context, owner, repo string, release *Release, fo fetchOptions) (*DownloadResult, error) {
+	tag := release.TagName
+	ctx = withEventScope(ctx, owner, repo, tag)
+
 	// 获取Release资产
-	assets := c.getReleaseAssets(release)
+	assets, err := c.selectAssets(release, fo.assetPattern)
+	if err != nil {
+		return nil, err
+	}
+	c.emitAssetsSelected(owner, repo, tag, assets)
 
 	// 如果没有资产且配置了下载源代码
 	if c.shouldDownloadSource(assets) {
@@ -193,179 +155,265 @@ func (c *Client) DownloadSpecificRelease(owner, repo, tag string) (string, error
 			zap.String("repo", repo),
 			zap.String("tag", tag),
 		)
-		return c.DownloadSourceCode(owner, repo, tag)
+		result, err := c.downloadSource(ctx, owner, repo, tag, fo)
+		if err != nil {
+			return nil, err
+		}
+		result.Release = release
+		return result, nil
+	}
+
+	// 没有可下载的资产
+	if len(assets) == 0 {
+		return nil, fmt.Errorf("Release %s %w", tag, ErrNoMatchingAsset)
 	}
 
 	// 下载资产
-	filePaths, err := c.downloadAssets(assets)
+	files, err := c.downloadAssetFiles(ctx, assets, c.releaseDir(owner, repo, tag))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// 如果只有一个文件，直接返回
-	if len(filePaths) == 1 {
-		// 如果配置了自动解压，解压文件
-		if c.options.AutoExtract {
-			extractedPath, err := c.extractFile(filePaths[0])
-			if err != nil {
-				c.logger.Warn("解压文件失败",
-					zap.String("filePath", filePaths[0]),
-					zap.Error(err),
-				)
-				// 解压失败不影响返回
-			} else {
-				filePaths[0] = extractedPath
-			}
+	// 记录到锁文件
+	if c.lockfile != nil {
+		if err := c.lockAssets(owner, repo, tag, assets, files); err != nil {
+			return nil, err
 		}
+	}
 
-		// 如果配置了目标目录，移动文件
-		if c.options.TargetDir != "" && c.options.TargetDir != c.options.CacheDir {
-			targetPath := filepath.Join(c.options.TargetDir, filepath.Base(filePaths[0]))
-			if err := c.moveFile(filePaths[0], targetPath); err != nil {
-				c.logger.Warn("移动文件失败",
-					zap.String("source", filePaths[0]),
-					zap.String("target", targetPath),
-					zap.Error(err),
-				)
-				// 移动失败不影响返回
-			} else {
-				filePaths[0] = targetPath
-			}
+	result := &DownloadResult{Owner: owner, Repo: repo, Tag: tag, Release: release, Files: files}
+	if err := c.finalizeFiles(ctx, result, fo); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// downloadAssetFiles 并发下载资产到dir，按资产顺序返回下载的文件信息
+func (c *Client) downloadAssetFiles(ctx context.Context, assets []*Asset, dir string) ([]DownloadedFile, error) {
+	filePaths, err := c.downloadAssets(ctx, assets, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	downloaded := make(map[string]bool, len(filePaths))
+	for _, filePath := range filePaths {
+		downloaded[filepath.Base(filePath)] = true
+	}
+
+	files := make([]DownloadedFile, 0, len(filePaths))
+	for _, asset := range assets {
+		if !downloaded[asset.Name] {
+			continue
+		}
+		file, err := newDownloadedFile(c.getAssetDownloadURL(asset), filepath.Join(dir, asset.Name))
+		if err != nil {
+			return nil, err
 		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// finalizeFiles 按配置解压下载的文件并移动到目标目录，更新结果中的路径
+// 只有一个文件时结果路径为该文件，有多个文件时为包含所有文件的目录
+func (c *Client) finalizeFiles(ctx context.Context, result *DownloadResult, fo fetchOptions) error {
+	ctx = withEventScope(ctx, result.Owner, result.Repo, result.Tag)
 
-		return filePaths[0], nil
+	// 如果只有一个文件，直接处理该文件
+	if len(result.Files) == 1 {
+		result.Path = c.finalizeFile(ctx, &result.Files[0], fo)
+		return nil
 	}
 
 	// 如果有多个文件，返回目录
-	dirPath := filepath.Join(c.options.CacheDir, fmt.Sprintf("%s-%s-%s", owner, repo, tag))
+	dirPath := c.releaseDir(result.Owner, result.Repo, result.Tag)
 	if err := os.MkdirAll(dirPath, 0755); err != nil {
-		return "", fmt.Errorf("创建目录失败: %w", err)
+		return fmt.Errorf("创建目录失败: %w", err)
 	}
 
-	// 移动所有文件到目录
-	for _, filePath := range filePaths {
-		targetPath := filepath.Join(dirPath, filepath.Base(filePath))
-		if err := c.moveFile(filePath, targetPath); err != nil {
-			c.logger.Warn("移动文件失败",
-				zap.String("source", filePath),
-				zap.String("target", targetPath),
-				zap.Error(err),
-			)
-			continue
+	// 移动所有文件到目录，资产已经下载在该目录中时不需要移动
+	for i := range result.Files {
+		file := &result.Files[i]
+		targetPath := filepath.Join(dirPath, filepath.Base(file.Path))
+		if file.Path != targetPath {
+			if err := c.moveFile(ctx, file.Path, targetPath); err != nil {
+				c.logger.Warn("移动文件失败",
+					zap.String("source", file.Path),
+					zap.String("target", targetPath),
+					zap.Error(err),
+				)
+				continue
+			}
+			file.Path = targetPath
 		}
 
 		// 如果配置了自动解压，解压文件
-		if c.options.AutoExtract {
-			c.extractFile(targetPath)
+		if fo.autoExtract {
+			if extractedPath, err := c.extractFile(ctx, targetPath); err == nil {
+				file.Path, file.ExtractedPath = "", extractedPath
+			}
 		}
 	}
 
 	// 如果配置了目标目录，移动目录
-	if c.options.TargetDir != "" && c.options.TargetDir != c.options.CacheDir {
-		targetDirPath := filepath.Join(c.options.TargetDir, filepath.Base(dirPath))
-		if err := c.moveFile(dirPath, targetDirPath); err != nil {
+	if fo.targetDir != "" && fo.targetDir != c.options.CacheDir {
+		targetDirPath := filepath.Join(fo.targetDir, filepath.Base(dirPath))
+		if err := c.moveFile(ctx, dirPath, targetDirPath); err != nil {
 			c.logger.Warn("移动目录失败",
 				zap.String("source", dirPath),
 				zap.String("target", targetDirPath),
 				zap.Error(err),
 			)
 		} else {
+			for i := range result.Files {
+				file := &result.Files[i]
+				file.Path = rebasePath(file.Path, dirPath, targetDirPath)
+				file.ExtractedPath = rebasePath(file.ExtractedPath, dirPath, targetDirPath)
+			}
 			dirPath = targetDirPath
 		}
 	}
 
-	return dirPath, nil
+	result.Path = dirPath
+	return nil
+}
+
+// finalizeFile 按配置解压单个文件并移动到目标目录，失败时保留原路径
+// 更新文件信息中的路径，返回处理后的路径
+func (c *Client) finalizeFile(ctx context.Context, file *DownloadedFile, fo fetchOptions) string {
+	// 如果配置了自动解压，解压文件
+	if fo.autoExtract {
+		extractedPath, err := c.extractFile(ctx, file.Path)
+		if err != nil {
+			c.logger.Warn("解压文件失败",
+				zap.String("filePath", file.Path),
+				zap.Error(err),
+			)
+			// 解压失败不影响返回
+		} else {
+			file.Path, file.ExtractedPath = "", extractedPath
+		}
+	}
+
+	// 如果配置了目标目录，移动文件
+	if fo.targetDir != "" && fo.targetDir != c.options.CacheDir {
+		filePath := file.finalPath()
+		targetPath := filepath.Join(fo.targetDir, filepath.Base(filePath))
+		if err := c.moveFile(ctx, filePath, targetPath); err != nil {
+			c.logger.Warn("移动文件失败",
+				zap.String("source", filePath),
+				zap.String("target", targetPath),
+				zap.Error(err),
+			)
+			// 移动失败不影响返回
+		} else if file.ExtractedPath != "" {
+			file.ExtractedPath = targetPath
+		} else {
+			file.Path = targetPath
+		}
+	}
+
+	return file.finalPath()
 }
 
 // DownloadSourceCode 下载源代码
-func (c *Client) DownloadSourceCode(owner, repo, tag string) (string, error) {
+func (c *Client) DownloadSourceCode(owner, repo, tag string) (*DownloadResult, error) {
+	ctx, span := c.startSpan(context.Background(), "DownloadSourceCode", repoAttr(owner, repo), attrTag.String(tag))
+
+	var result *DownloadResult
+	var err error
+	if c.options.FrozenLockfile {
+		// 锁定模式下直接下载锁文件中记录的源代码
+		result, err = c.downloadLockedSource(ctx, owner, repo, tag, c.defaultFetchOptions())
+	} else {
+		result, err = c.downloadSource(ctx, owner, repo, tag, c.defaultFetchOptions())
+	}
+
+	endDownloadSpan(span, result, err)
+	c.emitDownloadResult(owner, repo, result, err)
+	return result, err
+}
+
+// downloadSource 下载源代码，按配置解压并移动到目标目录
+func (c *Client) downloadSource(ctx context.Context, owner, repo, tag string, fo fetchOptions) (*DownloadResult, error) {
 	c.logger.Info("开始下载源代码",
 		zap.String("owner", owner),
 		zap.String("repo", repo),
 		zap.String("tag", tag),
 	)
 
+	// 没有指定Tag时使用最新的Tag
+	if tag == "" {
+		latestTag, err := c.getLatestTagName(ctx, owner, repo)
+		if err != nil {
+			return nil, err
+		}
+		tag = latestTag
+	}
+	ctx = withEventScope(ctx, owner, repo, tag)
+
 	// 获取源代码URL
-	url, err := c.getSourceCodeURL(owner, repo, tag)
+	url, err := c.getSourceCodeURL(ctx, owner, repo, tag)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// 构建文件名
-	fileName := fmt.Sprintf("%s-%s-%s.zip", owner, repo, tag)
+	// 构建文件名，扩展名与平台提供的压缩包格式一致
+	fileName := sourceFileName(owner, repo, tag, url)
 	filePath := filepath.Join(c.options.CacheDir, fileName)
 
 	// 下载文件
-	if err := c.downloadWithBuffer(url, filePath); err != nil {
-		return "", fmt.Errorf("下载源代码失败: %w", err)
+	if err := c.downloadSourceArchive(ctx, owner, repo, tag, url, filePath); err != nil {
+		return nil, fmt.Errorf("下载源代码失败: %w", err)
 	}
 
-	// 如果配置了自动解压，解压文件
-	if c.options.AutoExtract {
-		extractedPath, err := c.extractFile(filePath)
-		if err != nil {
-			c.logger.Warn("解压源代码失败",
-				zap.String("filePath", filePath),
-				zap.Error(err),
-			)
-			// 解压失败不影响返回
-		} else {
-			filePath = extractedPath
-		}
+	file, err := newDownloadedFile(url, filePath)
+	if err != nil {
+		return nil, err
 	}
 
-	// 如果配置了目标目录，移动文件
-	if c.options.TargetDir != "" && c.options.TargetDir != c.options.CacheDir {
-		targetPath := filepath.Join(c.options.TargetDir, filepath.Base(filePath))
-		if err := c.moveFile(filePath, targetPath); err != nil {
-			c.logger.Warn("移动源代码失败",
-				zap.String("source", filePath),
-				zap.String("target", targetPath),
-				zap.Error(err),
-			)
-			// 移动失败不影响返回
-		} else {
-			filePath = targetPath
+	// 记录到锁文件
+	if c.lockfile != nil {
+		if err := c.lockSource(owner, repo, tag, file); err != nil {
+			return nil, err
 		}
 	}
 
-	return filePath, nil
+	result := &DownloadResult{Owner: owner, Repo: repo, Tag: tag, Files: []DownloadedFile{file}, Source: true}
+	result.Path = c.finalizeFile(ctx, &result.Files[0], fo)
+	return result, nil
 }
 
-// downloadAssets 并发下载多个资产
-func (c *Client) downloadAssets(assets []*github.ReleaseAsset) ([]string, error) {
+// downloadAssets 并发下载多个资产到dir
+func (c *Client) downloadAssets(ctx context.Context, assets []*Asset, dir string) ([]string, error) {
 	c.logger.Info("开始并发下载资产",
 		zap.Int("assetCount", len(assets)),
 		zap.Int("concurrency", c.options.Concurrency),
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), c.options.Timeout)
+	ctx, cancel := context.WithTimeout(ctx, c.options.Timeout)
 	defer cancel()
 
 	var wg sync.WaitGroup
 	results := make(chan downloadResult, len(assets))
-	semaphore := make(chan struct{}, c.options.Concurrency)
 
 	// 启动goroutine下载每个资产
 	for _, asset := range assets {
 		wg.Add(1)
-		go func(a *github.ReleaseAsset) {
+		go func(a *Asset) {
 			defer wg.Done()
 
-			// 获取信号量
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
-
-			// 检查上下文是否已取消
-			select {
-			case <-ctx.Done():
-				results <- downloadResult{err: ctx.Err()}
+			// 获取全局下载名额，同一个Client的所有下载共享并发限制
+			release, err := c.acquireDownloadSlot(ctx)
+			if err != nil {
+				results <- downloadResult{err: err}
 				return
-			default:
 			}
+			defer release()
 
 			// 下载资产
-			filePath, err := c.downloadAsset(a)
+			filePath, err := c.downloadAsset(ctx, a, dir)
 			results <- downloadResult{filePath: filePath, err: err}
 		}(asset)
 	}
@@ -411,32 +459,55 @@ func (c *Client) downloadAssets(assets []*github.ReleaseAsset) ([]string, error)
 	return filePaths, nil
 }
 
-// downloadAsset 下载单个资产
-func (c *Client) downloadAsset(asset *github.ReleaseAsset) (string, error) {
+// acquireDownloadSlot 获取全局下载名额，返回释放名额的函数
+// 同一个Client的所有下载（资产和源代码）共享 Concurrency 限制
+func (c *Client) acquireDownloadSlot(ctx context.Context) (func(), error) {
+	select {
+	case c.downloadSlots <- struct{}{}:
+		return func() { <-c.downloadSlots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// releaseDir 返回Release的资产在缓存目录中的下载目录
+// 按仓库和Tag区分，并发下载不同仓库的同名资产时互不覆盖
+func (c *Client) releaseDir(owner, repo, tag string) string {
+	return filepath.Join(c.options.CacheDir, repoFileName(owner, repo)+"-"+tag)
+}
+
+// downloadAsset 下载单个资产到dir
+func (c *Client) downloadAsset(ctx context.Context, asset *Asset, dir string) (filePath string, err error) {
+	ctx, span := c.startSpan(ctx, "downloadAsset", attrAsset.String(asset.Name), attrAssetSize.Int64(asset.Size))
+	defer func() { endSpan(span, err) }()
+
 	c.logger.Info("开始下载资产",
-		zap.String("name", asset.GetName()),
-		zap.Int64("size", int64(asset.GetSize())),
+		zap.String("name", asset.Name),
+		zap.Int64("size", asset.Size),
 	)
 
 	// 获取下载URL
 	url := c.getAssetDownloadURL(asset)
 
 	// 构建文件名和路径
-	fileName := asset.GetName()
-	filePath := filepath.Join(c.options.CacheDir, fileName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建下载目录失败: %w", err)
+	}
+	filePath = filepath.Join(dir, asset.Name)
 
-	// 下载文件
-	if err := c.downloadWithBuffer(url, filePath); err != nil {
+	// 下载文件，公开地址返回404时使用需要认证的地址重试
+	authURL := func(d AuthenticatedDownloader) (string, bool) { return d.AuthenticatedAssetURL(asset) }
+	if err := c.downloadWithAuthFallback(ctx, url, filePath, authURL); err != nil {
 		c.logger.Error("下载资产失败",
-			zap.String("name", asset.GetName()),
+			zap.String("name", asset.Name),
 			zap.String("url", url),
 			zap.Error(err),
 		)
-		return "", fmt.Errorf("下载资产 %s 失败: %w", asset.GetName(), err)
+		return "", fmt.Errorf("下载资产 %s 失败: %w", asset.Name, err)
 	}
 
 	c.logger.Info("资产下载成功",
-		zap.String("name", asset.GetName()),
+		zap.String("name", asset.Name),
 		zap.String("path", filePath),
 	)
 
@@ -444,45 +515,156 @@ func (c *Client) downloadAsset(asset *github.ReleaseAsset) (string, error) {
 }
 
 // downloadWithBuffer 使用缓冲下载文件
-func (c *Client) downloadWithBuffer(url, filePath string) error {
+// 配置了镜像时依次尝试匹配的镜像地址，全部失败后再尝试原始地址
+func (c *Client) downloadWithBuffer(ctx context.Context, url, filePath string) (err error) {
+	start := time.Now()
+	defer func() { c.metrics.ObserveDownload(time.Since(start), err) }()
+
+	candidates := c.mirrorURLs(ctx, url)
+
+	for i, candidate := range candidates {
+		if err = c.downloadFromURL(ctx, candidate, filePath); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		if i < len(candidates)-1 {
+			c.logger.Warn("下载失败，尝试下一个地址",
+				zap.String("url", candidate),
+				zap.String("next", candidates[i+1]),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return err
+}
+
+// downloadSourceArchive 下载源代码压缩包，公开地址返回404时使用需要认证的地址重试
+// 与资产下载共享全局下载名额
+func (c *Client) downloadSourceArchive(ctx context.Context, owner, repo, tag, url, filePath string) error {
+	ctx = withEventScope(ctx, owner, repo, tag)
+	release, err := c.acquireDownloadSlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	authURL := func(d AuthenticatedDownloader) (string, bool) {
+		return d.AuthenticatedSourceArchiveURL(owner, repo, tag)
+	}
+	return c.downloadWithAuthFallback(ctx, url, filePath, authURL)
+}
+
+// downloadWithAuthFallback 下载文件，公开的下载地址返回404时（例如未标记为私有的私有仓库）
+// 使用authURL获取的需要认证的地址重试
+func (c *Client) downloadWithAuthFallback(ctx context.Context, url, filePath string, authURL func(AuthenticatedDownloader) (string, bool)) error {
+	err := c.downloadWithBuffer(ctx, url, filePath)
+	fallbackURL, ok := c.authFallbackURL(err, url, authURL)
+	if !ok {
+		return err
+	}
+	return c.downloadWithBuffer(ctx, fallbackURL, filePath)
+}
+
+// authFallbackURL 下载返回404时获取需要认证的备用地址，Provider不支持或地址相同时返回false
+func (c *Client) authFallbackURL(err error, url string, authURL func(AuthenticatedDownloader) (string, bool)) (string, bool) {
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusNotFound {
+		return "", false
+	}
+	downloader, ok := c.provider.(AuthenticatedDownloader)
+	if !ok {
+		return "", false
+	}
+	fallbackURL, ok := authURL(downloader)
+	if !ok || fallbackURL == url {
+		return "", false
+	}
+
+	c.logger.Info("下载地址返回404，使用需要认证的地址重试",
+		zap.String("url", url),
+		zap.String("fallback", fallbackURL),
+	)
+	return fallbackURL, true
+}
+
+// openURL 发送下载请求，按需添加认证信息，响应状态不是200时返回错误
+// 调用方负责关闭响应体
+func (c *Client) openURL(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	if authorizer, ok := c.provider.(RequestAuthorizer); ok {
+		if err := authorizer.AuthorizeRequest(req); err != nil {
+			return nil, fmt.Errorf("添加认证信息失败: %w", err)
+		}
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("发送请求失败: %w", err)
+	}
+
+	// 检查响应状态
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("下载失败: %w", responseError(resp))
+	}
+
+	return resp, nil
+}
+
+// downloadFromURL 从指定地址使用缓冲下载文件
+// 先写入 <文件>.part，下载完成后再重命名，失败时删除不完整的文件
+func (c *Client) downloadFromURL(ctx context.Context, url, filePath string) (err error) {
 	c.logger.Debug("开始缓冲下载",
 		zap.String("url", url),
 		zap.String("path", filePath),
 		zap.Int("bufferSize", c.options.BufferSize),
 	)
 
-	// 创建文件
-	file, err := os.Create(filePath)
+	// 创建临时文件
+	partPath := filePath + ".part"
+	file, err := os.Create(partPath)
 	if err != nil {
 		return fmt.Errorf("创建文件失败: %w", err)
 	}
-	defer file.Close()
+	defer func() {
+		file.Close()
+		if err != nil {
+			os.Remove(partPath)
+		}
+	}()
 
 	// 创建缓冲写入器
 	bufferedWriter := bufio.NewWriterSize(file, c.options.BufferSize)
 	defer bufferedWriter.Flush()
 
 	// 发送请求
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.openURL(ctx, url)
 	if err != nil {
-		return fmt.Errorf("发送请求失败: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
-	// 检查响应状态
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("下载失败，状态码: %d", resp.StatusCode)
-	}
-
 	// 获取文件大小
 	fileSize := resp.ContentLength
 
-	// 创建缓冲读取器
-	bufferedReader := bufio.NewReaderSize(resp.Body, c.options.BufferSize)
+	progress := &progressEmitter{
+		client: c,
+		event:  scopedEvent(ctx, Event{Type: EventDownloadProgress, Asset: filepath.Base(filePath), URL: url, Path: filePath, Total: fileSize}),
+	}
+	c.emit(scopedEvent(ctx, Event{Type: EventDownloadStarted, Asset: filepath.Base(filePath), URL: url, Path: filePath, Total: fileSize}))
+
+	// 创建缓冲读取器，配置了限速时按令牌桶读取
+	bufferedReader := bufio.NewReaderSize(c.limitBandwidth(ctx, resp.Body), c.options.BufferSize)
 
 	// 开始时间
 	startTime := time.Now()
 	var totalBytes int64
+	defer func() { c.metrics.AddDownloadBytes(totalBytes) }()
 
 	// 创建进度条（如果启用）
 	var bar *progressbar.ProgressBar
@@ -510,6 +692,7 @@ func (c *Client) downloadWithBuffer(url, filePath string) error {
 		}
 
 		totalBytes += int64(n)
+		progress.update(totalBytes)
 
 		// 更新进度条
 		if bar != nil {
@@ -532,6 +715,13 @@ func (c *Client) downloadWithBuffer(url, filePath string) error {
 	if err := bufferedWriter.Flush(); err != nil {
 		return fmt.Errorf("刷新缓冲区失败: %w", err)
 	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("关闭文件失败: %w", err)
+	}
+	if err := os.Rename(partPath, filePath); err != nil {
+		return fmt.Errorf("重命名文件失败: %w", err)
+	}
+	progress.flush()
 
 	// 关闭进度条
 	if bar != nil {
@@ -542,6 +732,8 @@ func (c *Client) downloadWithBuffer(url, filePath string) error {
 	duration := time.Since(startTime)
 	speed := float64(totalBytes) / duration.Seconds() / 1024 / 1024 // MB/s
 
+	spanFromContext(ctx).SetAttributes(attrBytes.Int64(totalBytes))
+
 	c.logger.Info("文件下载完成",
 		zap.String("url", url),
 		zap.String("path", filePath),
@@ -551,4 +743,4 @@ func (c *Client) downloadWithBuffer(url, filePath string) error {
 	)
 
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,109 @@
+package githubreleasedownloader
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrNoRelease 表示仓库没有Release或没有指定的Release
+	ErrNoRelease = errors.New("没有Release")
+
+	// ErrTagNotFound 表示没有指定Tag的Release，同时匹配 ErrNoRelease
+	ErrTagNotFound = fmt.Errorf("%w（Tag不存在）", ErrNoRelease)
+
+	// ErrNoMatchingAsset 表示Release中没有可下载的资产
+	ErrNoMatchingAsset = errors.New("没有匹配的资产")
+
+	// ErrUnsupportedArchive 表示文件不是支持解压的压缩格式
+	ErrUnsupportedArchive = errors.New("不支持的压缩格式")
+)
+
+// HTTPStatusError 表示请求返回了非预期的HTTP状态码
+type HTTPStatusError struct {
+	Code int    // HTTP状态码
+	URL  string // 请求地址
+	Err  error  // 原始错误，可能为nil
+}
+
+// Error 实现error接口
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("请求 %s 失败，状态码: %d", e.URL, e.Code)
+}
+
+// Unwrap 返回原始错误
+func (e *HTTPStatusError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError 表示触发了API或下载的速率限制
+type RateLimitError struct {
+	URL        string        // 请求地址
+	Reset      time.Time     // 限制解除的时间，未知时为零值
+	RetryAfter time.Duration // 服务器建议的重试等待时间，未知时为0
+	Err        error         // 原始错误，可能为nil
+}
+
+// Error 实现error接口
+func (e *RateLimitError) Error() string {
+	switch {
+	case !e.Reset.IsZero():
+		return fmt.Sprintf("请求 %s 触发速率限制，将在 %s 解除", e.URL, e.Reset.Format(time.RFC3339))
+	case e.RetryAfter > 0:
+		return fmt.Sprintf("请求 %s 触发速率限制，请在 %s 后重试", e.URL, e.RetryAfter)
+	default:
+		return fmt.Sprintf("请求 %s 触发速率限制", e.URL)
+	}
+}
+
+// Unwrap 返回原始错误
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// ChecksumMismatchError 表示下载文件的SHA-256与期望值不一致
+type ChecksumMismatchError struct {
+	Name     string // 文件名
+	Expected string // 期望的SHA-256
+	Actual   string // 实际的SHA-256
+}
+
+// Error 实现error接口
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("文件 %s 的SHA-256不匹配，期望 %s，实际 %s", e.Name, e.Expected, e.Actual)
+}
+
+// responseError 根据非预期的响应构建错误
+// 429以及剩余次数为0的403视为速率限制，返回 *RateLimitError，其他情况返回 *HTTPStatusError
+func responseError(resp *http.Response) error {
+	url := responseURL(resp)
+
+	limited := resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && rateLimitHeader(resp.Header, "Remaining") == "0")
+	if !limited {
+		return &HTTPStatusError{Code: resp.StatusCode, URL: url}
+	}
+
+	rateLimitErr := &RateLimitError{
+		URL: url,
+		Err: &HTTPStatusError{Code: resp.StatusCode, URL: url},
+	}
+	if reset, err := strconv.ParseInt(rateLimitHeader(resp.Header, "Reset"), 10, 64); err == nil {
+		rateLimitErr.Reset = time.Unix(reset, 0)
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		rateLimitErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return rateLimitErr
+}
+
+// rateLimitHeader 读取速率限制响应头，GitHub和Gitea使用 X-RateLimit- 前缀，GitLab使用 RateLimit- 前缀
+func rateLimitHeader(header http.Header, name string) string {
+	if value := header.Get("X-RateLimit-" + name); value != "" {
+		return value
+	}
+	return header.Get("RateLimit-" + name)
+}
@@ -0,0 +1,167 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"time"
+)
+
+// eventProgressInterval 下载进度事件的最小间隔
+const eventProgressInterval = 100 * time.Millisecond
+
+// EventType 事件类型
+type EventType int
+
+const (
+	EventReleaseResolved  EventType = iota + 1 // 已获取Release
+	EventAssetSelected                         // 已选择要下载的资产
+	EventDownloadStarted                       // 开始下载文件
+	EventDownloadProgress                      // 下载进度，按 eventProgressInterval 节流
+	EventChecksumVerified                      // SHA-256校验通过
+	EventExtractStarted                        // 开始解压
+	EventExtractFinished                       // 解压完成
+	EventFileMoved                             // 文件已移动
+	EventCompleted                             // 下载任务完成
+	EventFailed                                // 下载任务失败
+)
+
+// String 返回事件类型的名称
+func (t EventType) String() string {
+	switch t {
+	case EventReleaseResolved:
+		return "release_resolved"
+	case EventAssetSelected:
+		return "asset_selected"
+	case EventDownloadStarted:
+		return "download_started"
+	case EventDownloadProgress:
+		return "download_progress"
+	case EventChecksumVerified:
+		return "checksum_verified"
+	case EventExtractStarted:
+		return "extract_started"
+	case EventExtractFinished:
+		return "extract_finished"
+	case EventFileMoved:
+		return "file_moved"
+	case EventCompleted:
+		return "completed"
+	case EventFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// Event 下载过程中的事件，未涉及的字段为零值
+type Event struct {
+	Type   EventType // 事件类型
+	Time   time.Time // 事件时间
+	Owner  string    // 仓库所有者
+	Repo   string    // 仓库名称
+	Tag    string    // 版本Tag
+	Asset  string    // 资产或文件名
+	URL    string    // 下载地址
+	Source string    // 移动前的路径（EventFileMoved）
+	Path   string    // 相关的文件路径：下载目标、解压结果、移动后的路径或最终结果
+	Bytes  int64     // 已下载的字节数
+	Total  int64     // 文件总字节数，未知时为-1
+	Err    error     // 失败原因（EventFailed）
+}
+
+// EventHandler 事件处理函数
+type EventHandler func(Event)
+
+// emit 发送事件，未设置事件处理函数时忽略
+// 处理函数的调用是串行的，处理函数不需要自行加锁，但应尽快返回以免阻塞下载
+func (c *Client) emit(event Event) {
+	if c.options.EventHandler == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	c.eventMu.Lock()
+	defer c.eventMu.Unlock()
+	c.options.EventHandler(event)
+}
+
+// eventScopeKey 在context中保存当前下载任务的仓库和Tag
+type eventScopeKey struct{}
+
+// eventScope 下载任务的仓库和Tag，用于补全下载、解压和移动文件等底层事件
+type eventScope struct {
+	owner, repo, tag string
+}
+
+// withEventScope 返回携带仓库和Tag的context，之后通过 scopedEvent 发送的事件会自动补全这些字段
+func withEventScope(ctx context.Context, owner, repo, tag string) context.Context {
+	return context.WithValue(ctx, eventScopeKey{}, eventScope{owner: owner, repo: repo, tag: tag})
+}
+
+// scopedEvent 使用context中的仓库和Tag补全事件中为空的字段
+// 批量下载时不同仓库的同名文件（如 checksums.txt）可以据此区分
+func scopedEvent(ctx context.Context, event Event) Event {
+	scope, ok := ctx.Value(eventScopeKey{}).(eventScope)
+	if !ok {
+		return event
+	}
+	if event.Owner == "" {
+		event.Owner = scope.owner
+	}
+	if event.Repo == "" {
+		event.Repo = scope.repo
+	}
+	if event.Tag == "" {
+		event.Tag = scope.tag
+	}
+	return event
+}
+
+// emitResult 发送下载任务完成或失败的事件
+func (c *Client) emitResult(owner, repo, path string, err error) {
+	if err != nil {
+		c.emit(Event{Type: EventFailed, Owner: owner, Repo: repo, Err: err})
+		return
+	}
+	c.emit(Event{Type: EventCompleted, Owner: owner, Repo: repo, Path: path})
+}
+
+// emitDownloadResult 根据下载结果发送下载任务完成或失败的事件
+func (c *Client) emitDownloadResult(owner, repo string, result *DownloadResult, err error) {
+	if err != nil {
+		c.emit(Event{Type: EventFailed, Owner: owner, Repo: repo, Err: err})
+		return
+	}
+	c.emit(Event{Type: EventCompleted, Owner: owner, Repo: repo, Tag: result.Tag, Path: result.Path})
+}
+
+// emitAssetsSelected 为每个选中的资产发送事件
+func (c *Client) emitAssetsSelected(owner, repo, tag string, assets []*Asset) {
+	for _, asset := range assets {
+		c.emit(Event{Type: EventAssetSelected, Owner: owner, Repo: repo, Tag: tag, Asset: asset.Name, Total: asset.Size})
+	}
+}
+
+// progressEmitter 对下载进度事件进行节流
+type progressEmitter struct {
+	client   *Client
+	event    Event
+	lastEmit time.Time
+}
+
+// update 更新已下载的字节数，距离上次发送超过间隔时发送进度事件
+func (p *progressEmitter) update(bytes int64) {
+	p.event.Bytes = bytes
+	if time.Since(p.lastEmit) < eventProgressInterval {
+		return
+	}
+	p.flush()
+}
+
+// flush 立即发送当前进度
+func (p *progressEmitter) flush() {
+	p.lastEmit = time.Now()
+	p.event.Time = p.lastEmit
+	p.client.emit(p.event)
+}
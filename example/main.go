@@ -11,15 +11,15 @@ import (
 func main() {
 	// 创建客户端
 	client, err := githubreleasedownloader.NewClient(
-		githubreleasedownloader.WithConcurrency(5),              // 并发下载数量
-		githubreleasedownloader.WithBufferSize(8*1024*1024),     // 缓冲区大小（8MB）
-		githubreleasedownloader.WithTimeout(30*time.Minute),     // 下载超时
-		githubreleasedownloader.WithAutoExtract(true),           // 自动解压
-		githubreleasedownloader.WithTargetDir("./"),             // 目标目录
-		githubreleasedownloader.WithCheckLatest(false),          // 禁用检查最新版本
-		githubreleasedownloader.WithLoggerLevel("info"),         // 日志级别
-		githubreleasedownloader.WithProxyURL("127.0.0.1:10388"), // SOCKS5代理（可选）
-		githubreleasedownloader.WithShowProgress(true),          // 显示下载进度条
+		githubreleasedownloader.WithConcurrency(5),                       // 并发下载数量
+		githubreleasedownloader.WithBufferSize(8*1024*1024),              // 缓冲区大小（8MB）
+		githubreleasedownloader.WithTimeout(30*time.Minute),              // 下载超时
+		githubreleasedownloader.WithAutoExtract(true),                    // 自动解压
+		githubreleasedownloader.WithTargetDir("./"),                      // 目标目录
+		githubreleasedownloader.WithCheckLatest(false),                   // 禁用检查最新版本
+		githubreleasedownloader.WithLoggerLevel("info"),                  // 日志级别
+		githubreleasedownloader.WithProxyURL("socks5://127.0.0.1:10388"), // 代理（可选）
+		githubreleasedownloader.WithShowProgress(true),                   // 显示下载进度条
 	// githubreleasedownloader.WithAccessToken("your-token")  // GitHub访问令牌（可选）
 	)
 	if err != nil {
@@ -29,19 +29,22 @@ func main() {
 
 	// 下载最新版本示例
 	fmt.Println("=== 下载最新版本示例 ===")
-	filePath, err := client.DownloadLatestRelease("zyedidia", "eget")
+	result, err := client.DownloadLatestRelease("zyedidia", "eget")
 	if err != nil {
 		log.Fatalf("下载失败: %v", err)
 	}
-	fmt.Printf("下载成功: %s\n", filePath)
+	fmt.Printf("下载成功: %s (%s)\n", result.Path, result.Tag)
+	for _, file := range result.Files {
+		fmt.Printf("  %s %d bytes sha256:%s\n", file.Name, file.Size, file.SHA256)
+	}
 
 	// 示例2: 下载指定版本
 	// fmt.Println("\n=== 下载指定版本 ===")
-	// path, err = client.DownloadSpecificRelease("golang", "go", "go1.21.0")
+	// result, err = client.DownloadSpecificRelease("golang", "go", "go1.21.0")
 	// if err != nil {
 	// 	log.Printf("下载失败: %v", err)
 	// } else {
-	// 	fmt.Printf("下载成功: %s\n", path)
+	// 	fmt.Printf("下载成功: %s\n", result.Path)
 	// }
 
 	// 示例3: 检查版本是否为最新
@@ -59,10 +62,10 @@ func main() {
 
 	// // 示例4: 下载源代码（当没有Release文件时）
 	// fmt.Println("\n=== 下载源代码 ===")
-	// path, err = client.DownloadSourceCode("golang", "go", "go1.21.0")
+	// result, err = client.DownloadSourceCode("golang", "go", "go1.21.0")
 	// if err != nil {
 	// 	log.Printf("下载失败: %v", err)
 	// } else {
-	// 	fmt.Printf("下载成功: %s\n", path)
+	// 	fmt.Printf("下载成功: %s\n", result.Path)
 	// }
 }
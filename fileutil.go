@@ -4,9 +4,14 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -14,15 +19,17 @@ import (
 )
 
 // extractFile 解压文件
-func (c *Client) extractFile(filePath string) (string, error) {
+func (c *Client) extractFile(ctx context.Context, filePath string) (extractedDir string, err error) {
+	_, span := c.startSpan(ctx, "extractFile", attrAsset.String(filepath.Base(filePath)), attrPath.String(filePath))
+	defer func() { endSpan(span, err) }()
+
 	c.logger.Info("开始解压文件",
 		zap.String("filePath", filePath),
 	)
+	c.emit(scopedEvent(ctx, Event{Type: EventExtractStarted, Asset: filepath.Base(filePath), Path: filePath}))
 
 	// 获取文件扩展名
 	lowerPath := strings.ToLower(filePath)
-	var extractedDir string
-	var err error
 
 	// 先检查特殊的双重扩展名
 	if strings.HasSuffix(lowerPath, ".tar.gz") || strings.HasSuffix(lowerPath, ".tgz") {
@@ -36,7 +43,7 @@ func (c *Client) extractFile(filePath string) (string, error) {
 		case ".gz":
 			extractedDir, err = c.extractGz(filePath)
 		default:
-			return "", fmt.Errorf("不支持的压缩格式: %s", ext)
+			return "", fmt.Errorf("%w: %s", ErrUnsupportedArchive, ext)
 		}
 	}
 
@@ -45,6 +52,7 @@ func (c *Client) extractFile(filePath string) (string, error) {
 			zap.String("filePath", filePath),
 			zap.Error(err),
 		)
+		c.metrics.IncExtractFailures()
 		return "", err
 	}
 
@@ -61,10 +69,58 @@ func (c *Client) extractFile(filePath string) (string, error) {
 		zap.String("filePath", filePath),
 		zap.String("extractedDir", extractedDir),
 	)
+	c.emit(scopedEvent(ctx, Event{Type: EventExtractFinished, Asset: filepath.Base(filePath), Path: extractedDir}))
 
 	return extractedDir, nil
 }
 
+// isSupportedArchive 检查文件是否为支持解压的压缩格式
+func isSupportedArchive(filePath string) bool {
+	lowerPath := strings.ToLower(filePath)
+	if strings.HasSuffix(lowerPath, ".tar.gz") || strings.HasSuffix(lowerPath, ".tgz") {
+		return true
+	}
+	switch filepath.Ext(lowerPath) {
+	case ".zip", ".gz":
+		return true
+	}
+	return false
+}
+
+// extractedPath 返回压缩文件解压后的路径
+// zip和gz去掉扩展名，tar.gz去掉两级扩展名（如 xxx.tar.gz 解压到 xxx）
+func extractedPath(filePath string) string {
+	lowerPath := strings.ToLower(filePath)
+	if strings.HasSuffix(lowerPath, ".tar.gz") || strings.HasSuffix(lowerPath, ".tgz") {
+		firstExt := filepath.Ext(filePath)                        // .gz
+		withoutFirstExt := strings.TrimSuffix(filePath, firstExt) // xxx.tar
+		secondExt := filepath.Ext(withoutFirstExt)                // .tar
+		return strings.TrimSuffix(withoutFirstExt, secondExt)     // xxx
+	}
+	return strings.TrimSuffix(filePath, filepath.Ext(filePath))
+}
+
+// repoFileName 构建仓库相关缓存文件的名称前缀
+// GitLab的owner可能包含子组（如 group/subgroup），其中的 / 替换为 -，避免产生多级目录
+func repoFileName(owner, repo string) string {
+	return strings.ReplaceAll(owner, "/", "-") + "-" + repo
+}
+
+// sourceFileName 构建源代码压缩包的文件名，扩展名取自下载地址（如 .zip、.tar.gz），
+// 地址中没有压缩包扩展名时（如GitHub API的zipball）使用 .zip
+func sourceFileName(owner, repo, tag, sourceURL string) string {
+	ext := ".zip"
+	if u, err := url.Parse(sourceURL); err == nil {
+		lowerPath := strings.ToLower(u.Path)
+		if strings.HasSuffix(lowerPath, ".tar.gz") {
+			ext = ".tar.gz"
+		} else if e := path.Ext(lowerPath); e == ".tgz" || e == ".zip" {
+			ext = e
+		}
+	}
+	return fmt.Sprintf("%s-%s%s", repoFileName(owner, repo), tag, ext)
+}
+
 // extractZip 解压ZIP文件
 func (c *Client) extractZip(filePath string) (string, error) {
 	// 打开ZIP文件
@@ -75,7 +131,7 @@ func (c *Client) extractZip(filePath string) (string, error) {
 	defer r.Close()
 
 	// 创建解压目录
-	extractedDir := strings.TrimSuffix(filePath, filepath.Ext(filePath))
+	extractedDir := extractedPath(filePath)
 	if err := os.MkdirAll(extractedDir, 0755); err != nil {
 		return "", fmt.Errorf("创建解压目录失败: %w", err)
 	}
@@ -149,10 +205,7 @@ func (c *Client) extractTarGz(filePath string) (string, error) {
 	tarReader := tar.NewReader(gzipReader)
 
 	// 创建解压目录
-	firstExt := filepath.Ext(filePath)                             // .gz
-	withoutFirstExt := strings.TrimSuffix(filePath, firstExt)      // xxx.tar
-	secondExt := filepath.Ext(withoutFirstExt)                     // .tar
-	extractedDir := strings.TrimSuffix(withoutFirstExt, secondExt) // xxx
+	extractedDir := extractedPath(filePath)
 
 	if err := os.MkdirAll(extractedDir, 0755); err != nil {
 		return "", fmt.Errorf("创建解压目录失败: %w", err)
@@ -245,7 +298,7 @@ func (c *Client) extractGz(filePath string) (string, error) {
 	defer gzipReader.Close()
 
 	// 创建目标文件
-	targetPath := strings.TrimSuffix(filePath, filepath.Ext(filePath))
+	targetPath := extractedPath(filePath)
 	dst, err := os.Create(targetPath)
 	if err != nil {
 		return "", fmt.Errorf("创建目标文件失败: %w", err)
@@ -262,7 +315,10 @@ func (c *Client) extractGz(filePath string) (string, error) {
 }
 
 // moveFile 移动文件或目录
-func (c *Client) moveFile(sourcePath, targetPath string) error {
+func (c *Client) moveFile(ctx context.Context, sourcePath, targetPath string) (err error) {
+	_, span := c.startSpan(ctx, "moveFile", attrSource.String(sourcePath), attrPath.String(targetPath))
+	defer func() { endSpan(span, err) }()
+
 	c.logger.Info("开始移动文件",
 		zap.String("source", sourcePath),
 		zap.String("target", targetPath),
@@ -306,6 +362,7 @@ func (c *Client) moveFile(sourcePath, targetPath string) error {
 		zap.String("source", sourcePath),
 		zap.String("target", targetPath),
 	)
+	c.emit(scopedEvent(ctx, Event{Type: EventFileMoved, Asset: filepath.Base(targetPath), Source: sourcePath, Path: targetPath}))
 
 	return nil
 }
@@ -373,3 +430,19 @@ func (c *Client) copyFile(sourcePath, targetPath string) error {
 
 	return nil
 }
+
+// fileSHA256 计算文件的SHA-256值（十六进制小写）
+func fileSHA256(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("计算SHA-256失败: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,193 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// giteaPageSize Gitea分页获取Release时每页请求的数量，服务器可能返回更少
+const giteaPageSize = 50
+
+// giteaProvider 使用Gitea/Forgejo API获取Release
+type giteaProvider struct {
+	httpClient  *http.Client
+	baseURL     string             // 实例地址，以/结尾
+	host        string             // 实例的主机名（包括端口），只为该主机的请求添加访问令牌
+	tokenSource oauth2.TokenSource // 访问令牌来源，为nil时匿名访问
+}
+
+// giteaRelease Gitea API返回的Release
+type giteaRelease struct {
+	TagName     string       `json:"tag_name"`
+	Name        string       `json:"name"`
+	Body        string       `json:"body"`
+	Draft       bool         `json:"draft"`
+	Prerelease  bool         `json:"prerelease"`
+	HTMLURL     string       `json:"html_url"`
+	PublishedAt time.Time    `json:"published_at"`
+	Assets      []giteaAsset `json:"assets"`
+}
+
+// giteaAsset Gitea API返回的资产
+type giteaAsset struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// newGiteaProvider 创建Gitea/Forgejo的ReleaseProvider
+func newGiteaProvider(httpClient *http.Client, baseURL string, tokenSource oauth2.TokenSource) (*giteaProvider, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("无效的Gitea地址: %q", baseURL)
+	}
+
+	return &giteaProvider{
+		httpClient:  httpClient,
+		baseURL:     strings.TrimSuffix(u.String(), "/") + "/",
+		host:        u.Host,
+		tokenSource: tokenSource,
+	}, nil
+}
+
+// LatestRelease 获取最新的Release
+func (p *giteaProvider) LatestRelease(ctx context.Context, owner, repo string) (*Release, error) {
+	var release giteaRelease
+	found, err := p.get(ctx, p.repoAPIURL(owner, repo, "releases/latest"), &release)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("仓库 %s/%s %w", owner, repo, ErrNoRelease)
+	}
+	return release.convert(), nil
+}
+
+// ReleaseByTag 通过Tag获取Release
+func (p *giteaProvider) ReleaseByTag(ctx context.Context, owner, repo, tag string) (*Release, error) {
+	var release giteaRelease
+	found, err := p.get(ctx, p.repoAPIURL(owner, repo, "releases/tags/"+url.PathEscape(tag)), &release)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: 仓库 %s/%s 中没有Tag为 %s 的Release", ErrTagNotFound, owner, repo, tag)
+	}
+	return release.convert(), nil
+}
+
+// ListReleases 分页获取仓库的所有Release
+func (p *giteaProvider) ListReleases(ctx context.Context, owner, repo string) ([]*Release, error) {
+	var releases []*Release
+	for page := 1; ; page++ {
+		var pageReleases []giteaRelease
+		endpoint := p.repoAPIURL(owner, repo, fmt.Sprintf("releases?page=%d&limit=%d", page, giteaPageSize))
+		found, err := p.get(ctx, endpoint, &pageReleases)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf("仓库 %s/%s 不存在", owner, repo)
+		}
+
+		// 服务器的 MAX_RESPONSE_ITEMS 小于请求的数量时每页会少于giteaPageSize，只有空页表示结束
+		if len(pageReleases) == 0 {
+			break
+		}
+		for _, release := range pageReleases {
+			releases = append(releases, release.convert())
+		}
+	}
+	return releases, nil
+}
+
+// AssetDownloadURL 获取资产的下载URL
+func (p *giteaProvider) AssetDownloadURL(asset *Asset) string {
+	return asset.BrowserDownloadURL
+}
+
+// SourceArchiveURL 获取源代码压缩包URL
+// Gitea的源代码下载URL格式为: {baseURL}/{owner}/{repo}/archive/{tag}.zip
+func (p *giteaProvider) SourceArchiveURL(owner, repo, tag string) string {
+	return fmt.Sprintf("%s%s/%s/archive/%s.zip", p.baseURL, owner, repo, url.PathEscape(tag))
+}
+
+// AuthorizeRequest 为指向Gitea实例的请求添加访问令牌，私有仓库的附件和源代码需要认证
+func (p *giteaProvider) AuthorizeRequest(req *http.Request) error {
+	if req.URL.Host != p.host {
+		return nil
+	}
+	token, err := bearerToken(p.tokenSource)
+	if err != nil || token == "" {
+		return err
+	}
+	// 使用Authorization头，跨域重定向时net/http会自动移除，不会泄露给第三方
+	req.Header.Set("Authorization", "token "+token)
+	return nil
+}
+
+// repoAPIURL 构建仓库API地址
+func (p *giteaProvider) repoAPIURL(owner, repo, path string) string {
+	return fmt.Sprintf("%sapi/v1/repos/%s/%s/%s", p.baseURL, url.PathEscape(owner), url.PathEscape(repo), path)
+}
+
+// get 请求API并解析JSON响应，资源不存在时返回false
+func (p *giteaProvider) get(ctx context.Context, endpoint string, v any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if err := p.AuthorizeRequest(req); err != nil {
+		return false, err
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("发送请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, responseError(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	return true, nil
+}
+
+// convert 将Gitea的Release转换为Release
+func (r *giteaRelease) convert() *Release {
+	release := &Release{
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Body,
+		Draft:       r.Draft,
+		Prerelease:  r.Prerelease,
+		HTMLURL:     r.HTMLURL,
+		PublishedAt: r.PublishedAt,
+	}
+	for _, asset := range r.Assets {
+		release.Assets = append(release.Assets, &Asset{
+			ID:                 asset.ID,
+			Name:               asset.Name,
+			Size:               asset.Size,
+			BrowserDownloadURL: asset.BrowserDownloadURL,
+		})
+	}
+	return release
+}
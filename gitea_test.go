@@ -0,0 +1,334 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+// giteaMaxResponseItems 模拟服务器的 MAX_RESPONSE_ITEMS，小于客户端请求的每页数量
+const giteaMaxResponseItems = 2
+
+// giteaTestToken 测试使用的访问令牌
+const giteaTestToken = "secret"
+
+// giteaStandIn 模拟Gitea API的测试服务器，记录收到的Authorization头
+type giteaStandIn struct {
+	*httptest.Server
+	releases []giteaRelease
+	private  bool // 为true时附件和源代码需要访问令牌，否则返回404
+
+	mu     sync.Mutex
+	auth   []string
+	limits []string
+}
+
+// newGiteaStandIn 启动模拟仓库 owner/repo 的Gitea服务器，releases按从新到旧排列
+func newGiteaStandIn(t *testing.T, releases []giteaRelease) *giteaStandIn {
+	t.Helper()
+
+	s := &giteaStandIn{releases: releases}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/owner/repo/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		s.record(r)
+		if len(s.releases) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, s.releases[0])
+	})
+	mux.HandleFunc("GET /api/v1/repos/owner/repo/releases/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		s.record(r)
+		for _, release := range s.releases {
+			if release.TagName == r.PathValue("tag") {
+				writeJSON(w, release)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("GET /api/v1/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		s.record(r)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		start := min((page-1)*giteaMaxResponseItems, len(s.releases))
+		end := min(start+giteaMaxResponseItems, len(s.releases))
+		writeJSON(w, s.releases[start:end])
+	})
+
+	mux.HandleFunc("GET /owner/repo/releases/download/{tag}/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.authorized(r) {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "asset %s %s", r.PathValue("tag"), r.PathValue("name"))
+	})
+	mux.HandleFunc("GET /owner/repo/archive/{archive}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.authorized(r) {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "source %s", r.PathValue("archive"))
+	})
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// record 记录请求的Authorization头和每页数量
+func (s *giteaStandIn) record(r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auth = append(s.auth, r.Header.Get("Authorization"))
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		s.limits = append(s.limits, limit)
+	}
+}
+
+// authorized 检查私有仓库的下载请求是否携带了正确的访问令牌
+func (s *giteaStandIn) authorized(r *http.Request) bool {
+	return !s.private || r.Header.Get("Authorization") == "token "+giteaTestToken
+}
+
+// writeJSON 以JSON写入响应
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// testGiteaReleases 生成n个从新到旧排列的Release，每个Release有一个资产
+func testGiteaReleases(baseURL string, n int) []giteaRelease {
+	releases := make([]giteaRelease, 0, n)
+	for i := n; i >= 1; i-- {
+		tag := fmt.Sprintf("v1.%d.0", i)
+		releases = append(releases, giteaRelease{
+			TagName: tag,
+			Name:    "Release " + tag,
+			Body:    "notes for " + tag,
+			HTMLURL: baseURL + "/owner/repo/releases/tag/" + tag,
+			Assets: []giteaAsset{{
+				ID:                 int64(i),
+				Name:               "tool_linux_amd64.tar.gz",
+				Size:               int64(100 + i),
+				BrowserDownloadURL: baseURL + "/owner/repo/releases/download/" + tag + "/tool_linux_amd64.tar.gz",
+			}},
+		})
+	}
+	return releases
+}
+
+// newTestGiteaProvider 创建连接到测试服务器的giteaProvider
+func newTestGiteaProvider(t *testing.T, server *giteaStandIn, tokenSource oauth2.TokenSource) *giteaProvider {
+	t.Helper()
+	provider, err := newGiteaProvider(server.Client(), server.URL, tokenSource)
+	if err != nil {
+		t.Fatalf("newGiteaProvider() error = %v", err)
+	}
+	return provider
+}
+
+func TestGiteaProviderLatestRelease(t *testing.T) {
+	server := newGiteaStandIn(t, nil)
+	server.releases = testGiteaReleases(server.URL, 3)
+	provider := newTestGiteaProvider(t, server, nil)
+
+	release, err := provider.LatestRelease(context.Background(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("LatestRelease() error = %v", err)
+	}
+	if release.TagName != "v1.3.0" || release.Name != "Release v1.3.0" || release.Body != "notes for v1.3.0" {
+		t.Errorf("LatestRelease() = %+v, want v1.3.0", release)
+	}
+	if len(release.Assets) != 1 || release.Assets[0].ID != 3 || release.Assets[0].Size != 103 {
+		t.Errorf("LatestRelease() assets = %+v", release.Assets)
+	}
+
+	server.releases = nil
+	if _, err := provider.LatestRelease(context.Background(), "owner", "repo"); !errors.Is(err, ErrNoRelease) {
+		t.Errorf("LatestRelease() without releases error = %v, want ErrNoRelease", err)
+	}
+}
+
+func TestGiteaProviderReleaseByTag(t *testing.T) {
+	server := newGiteaStandIn(t, nil)
+	server.releases = testGiteaReleases(server.URL, 3)
+	provider := newTestGiteaProvider(t, server, nil)
+
+	release, err := provider.ReleaseByTag(context.Background(), "owner", "repo", "v1.2.0")
+	if err != nil {
+		t.Fatalf("ReleaseByTag() error = %v", err)
+	}
+	if release.TagName != "v1.2.0" {
+		t.Errorf("ReleaseByTag() tag = %s, want v1.2.0", release.TagName)
+	}
+
+	_, err = provider.ReleaseByTag(context.Background(), "owner", "repo", "v9.9.9")
+	if !errors.Is(err, ErrTagNotFound) || !errors.Is(err, ErrNoRelease) {
+		t.Errorf("ReleaseByTag() missing tag error = %v, want ErrTagNotFound", err)
+	}
+}
+
+func TestGiteaProviderListReleasesShortPages(t *testing.T) {
+	server := newGiteaStandIn(t, nil)
+	server.releases = testGiteaReleases(server.URL, 5)
+	provider := newTestGiteaProvider(t, server, nil)
+
+	releases, err := provider.ListReleases(context.Background(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("ListReleases() error = %v", err)
+	}
+	if len(releases) != 5 {
+		t.Fatalf("ListReleases() returned %d releases, want 5", len(releases))
+	}
+	for i, release := range releases {
+		if want := fmt.Sprintf("v1.%d.0", 5-i); release.TagName != want {
+			t.Errorf("ListReleases()[%d] = %s, want %s", i, release.TagName, want)
+		}
+	}
+	// 3个非空页加1个空页
+	if len(server.limits) != 4 {
+		t.Errorf("ListReleases() requested %d pages, want 4", len(server.limits))
+	}
+	for _, limit := range server.limits {
+		if limit != strconv.Itoa(giteaPageSize) {
+			t.Errorf("ListReleases() limit = %s, want %d", limit, giteaPageSize)
+		}
+	}
+}
+
+func TestGiteaProviderDownloadURLs(t *testing.T) {
+	server := newGiteaStandIn(t, nil)
+	server.releases = testGiteaReleases(server.URL, 1)
+	provider := newTestGiteaProvider(t, server, nil)
+
+	release, err := provider.LatestRelease(context.Background(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("LatestRelease() error = %v", err)
+	}
+
+	wantAsset := server.URL + "/owner/repo/releases/download/v1.1.0/tool_linux_amd64.tar.gz"
+	if got := provider.AssetDownloadURL(release.Assets[0]); got != wantAsset {
+		t.Errorf("AssetDownloadURL() = %s, want %s", got, wantAsset)
+	}
+
+	wantSource := server.URL + "/owner/repo/archive/v1.1.0.zip"
+	if got := provider.SourceArchiveURL("owner", "repo", "v1.1.0"); got != wantSource {
+		t.Errorf("SourceArchiveURL() = %s, want %s", got, wantSource)
+	}
+}
+
+func TestGiteaProviderAuth(t *testing.T) {
+	tests := []struct {
+		name        string
+		tokenSource oauth2.TokenSource
+		want        string
+	}{
+		{name: "anonymous", want: ""},
+		{name: "token", tokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: giteaTestToken}), want: "token " + giteaTestToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newGiteaStandIn(t, nil)
+			server.releases = testGiteaReleases(server.URL, 1)
+			provider := newTestGiteaProvider(t, server, tt.tokenSource)
+
+			ctx := context.Background()
+			if _, err := provider.LatestRelease(ctx, "owner", "repo"); err != nil {
+				t.Fatalf("LatestRelease() error = %v", err)
+			}
+			if _, err := provider.ReleaseByTag(ctx, "owner", "repo", "v1.1.0"); err != nil {
+				t.Fatalf("ReleaseByTag() error = %v", err)
+			}
+			if _, err := provider.ListReleases(ctx, "owner", "repo"); err != nil {
+				t.Fatalf("ListReleases() error = %v", err)
+			}
+
+			for _, auth := range server.auth {
+				if auth != tt.want {
+					t.Errorf("Authorization = %q, want %q", auth, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGiteaProviderAuthorizeRequest(t *testing.T) {
+	server := newGiteaStandIn(t, nil)
+	tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: giteaTestToken})
+
+	tests := []struct {
+		name        string
+		tokenSource oauth2.TokenSource
+		url         string
+		want        string
+	}{
+		{name: "same host", tokenSource: tokenSource, url: server.URL + "/owner/repo/archive/v1.1.0.zip", want: "token " + giteaTestToken},
+		{name: "other host", tokenSource: tokenSource, url: "https://objects.example.com/owner/repo/asset"},
+		{name: "anonymous", url: server.URL + "/owner/repo/archive/v1.1.0.zip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTestGiteaProvider(t, server, tt.tokenSource)
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := provider.AuthorizeRequest(req); err != nil {
+				t.Fatalf("AuthorizeRequest() error = %v", err)
+			}
+			if got := req.Header.Get("Authorization"); got != tt.want {
+				t.Errorf("Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGiteaPrivateDownload(t *testing.T) {
+	server := newGiteaStandIn(t, nil)
+	server.releases = testGiteaReleases(server.URL, 1)
+	server.private = true
+
+	client, err := NewClient(
+		WithGiteaURL(server.URL),
+		WithAccessToken(giteaTestToken),
+		WithCacheDir(t.TempDir()),
+		WithAutoExtract(false),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	result, err := client.DownloadSpecificRelease("owner", "repo", "v1.1.0")
+	if err != nil {
+		t.Fatalf("DownloadSpecificRelease() error = %v", err)
+	}
+	if data, _ := os.ReadFile(result.Path); string(data) != "asset v1.1.0 tool_linux_amd64.tar.gz" {
+		t.Errorf("downloaded asset = %q", data)
+	}
+
+	result, err = client.DownloadSourceCode("owner", "repo", "v1.1.0")
+	if err != nil {
+		t.Fatalf("DownloadSourceCode() error = %v", err)
+	}
+	if data, _ := os.ReadFile(result.Path); string(data) != "source v1.1.0.zip" {
+		t.Errorf("downloaded source = %q", data)
+	}
+}
+
+func TestNewGiteaProviderInvalidURL(t *testing.T) {
+	if _, err := newGiteaProvider(http.DefaultClient, "gitea.example.com", nil); err == nil {
+		t.Error("newGiteaProvider() with URL without scheme succeeded, want error")
+	}
+}
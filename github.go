@@ -2,19 +2,22 @@ package githubreleasedownloader
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
 	"runtime"
 	"strings"
 
-	"github.com/google/go-github/v76/github"
+	"github.com/Masterminds/semver/v3"
 	"go.uber.org/zap"
 )
 
 // getLatestRelease 获取最新的Release
-func (c *Client) getLatestRelease(owner, repo string) (*github.RepositoryRelease, error) {
-	ctx := context.Background()
+func (c *Client) getLatestRelease(ctx context.Context, owner, repo string) (release *Release, err error) {
+	ctx, span := c.startSpan(ctx, "getLatestRelease", repoAttr(owner, repo))
+	defer func() { endSpan(span, err) }()
 	
-	release, resp, err := c.githubClient.Repositories.GetLatestRelease(ctx, owner, repo)
+	release, err = c.provider.LatestRelease(withMetricsEndpoint(ctx, "latest_release"), owner, repo)
 	if err != nil {
 		c.logger.Error("获取最新Release失败",
 			zap.String("owner", owner),
@@ -22,9 +25,9 @@ func (c *Client) getLatestRelease(owner, repo string) (*github.RepositoryRelease
 			zap.Error(err),
 		)
 		
-		// 检查是否是因为没有Release
-		if resp != nil && resp.StatusCode == 404 {
-			return nil, fmt.Errorf("仓库 %s/%s 没有Release", owner, repo)
+		// 没有Release时直接返回
+		if errors.Is(err, ErrNoRelease) {
+			return nil, err
 		}
 		
 		return nil, fmt.Errorf("获取最新Release失败: %w", err)
@@ -33,18 +36,22 @@ func (c *Client) getLatestRelease(owner, repo string) (*github.RepositoryRelease
 	c.logger.Info("获取最新Release成功",
 		zap.String("owner", owner),
 		zap.String("repo", repo),
-		zap.String("tag", release.GetTagName()),
-		zap.String("name", release.GetName()),
+		zap.String("tag", release.TagName),
+		zap.String("name", release.Name),
 	)
 	
+	c.emit(Event{Type: EventReleaseResolved, Owner: owner, Repo: repo, Tag: release.TagName})
+	span.SetAttributes(attrTag.String(release.TagName))
+	
 	return release, nil
 }
 
 // getReleaseByTag 通过Tag获取Release
-func (c *Client) getReleaseByTag(owner, repo, tag string) (*github.RepositoryRelease, error) {
-	ctx := context.Background()
+func (c *Client) getReleaseByTag(ctx context.Context, owner, repo, tag string) (release *Release, err error) {
+	ctx, span := c.startSpan(ctx, "getReleaseByTag", repoAttr(owner, repo), attrTag.String(tag))
+	defer func() { endSpan(span, err) }()
 	
-	release, resp, err := c.githubClient.Repositories.GetReleaseByTag(ctx, owner, repo, tag)
+	release, err = c.provider.ReleaseByTag(withMetricsEndpoint(ctx, "release_by_tag"), owner, repo, tag)
 	if err != nil {
 		c.logger.Error("通过Tag获取Release失败",
 			zap.String("owner", owner),
@@ -53,9 +60,9 @@ func (c *Client) getReleaseByTag(owner, repo, tag string) (*github.RepositoryRel
 			zap.Error(err),
 		)
 		
-		// 检查是否是因为Tag不存在
-		if resp != nil && resp.StatusCode == 404 {
-			return nil, fmt.Errorf("仓库 %s/%s 中没有Tag为 %s 的Release", owner, repo, tag)
+		// Tag不存在时直接返回
+		if errors.Is(err, ErrNoRelease) {
+			return nil, err
 		}
 		
 		return nil, fmt.Errorf("通过Tag获取Release失败: %w", err)
@@ -64,27 +71,111 @@ func (c *Client) getReleaseByTag(owner, repo, tag string) (*github.RepositoryRel
 	c.logger.Info("通过Tag获取Release成功",
 		zap.String("owner", owner),
 		zap.String("repo", repo),
-		zap.String("tag", release.GetTagName()),
-		zap.String("name", release.GetName()),
+		zap.String("tag", release.TagName),
+		zap.String("name", release.Name),
 	)
 	
+	c.emit(Event{Type: EventReleaseResolved, Owner: owner, Repo: repo, Tag: release.TagName})
+	span.SetAttributes(attrTag.String(release.TagName))
+	
 	return release, nil
 }
 
+// listReleases 获取仓库的所有Release
+func (c *Client) listReleases(ctx context.Context, owner, repo string) (releases []*Release, err error) {
+	ctx, span := c.startSpan(ctx, "listReleases", repoAttr(owner, repo))
+	defer func() { endSpan(span, err) }()
+	
+	releases, err = c.provider.ListReleases(withMetricsEndpoint(ctx, "list_releases"), owner, repo)
+	if err != nil {
+		c.logger.Error("获取Release列表失败",
+			zap.String("owner", owner),
+			zap.String("repo", repo),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("获取Release列表失败: %w", err)
+	}
+	
+	c.logger.Info("获取Release列表成功",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.Int("count", len(releases)),
+	)
+	
+	return releases, nil
+}
+
+// resolveRelease 根据版本描述获取Release
+// 版本描述为空或"latest"时获取最新Release，为semver约束（如"^1.2"、">=1.0, <2.0"）时
+// 获取满足约束的最高正式版本，否则作为Tag精确匹配
+func (c *Client) resolveRelease(ctx context.Context, owner, repo, version string) (*Release, error) {
+	if version == "" || version == "latest" {
+		return c.getLatestRelease(ctx, owner, repo)
+	}
+	
+	if !isVersionConstraint(version) {
+		return c.getReleaseByTag(ctx, owner, repo, version)
+	}
+	
+	constraint, err := semver.NewConstraint(version)
+	if err != nil {
+		return nil, fmt.Errorf("无效的版本约束 %q: %w", version, err)
+	}
+	
+	releases, err := c.listReleases(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	
+	var best *Release
+	var bestVersion *semver.Version
+	for _, release := range releases {
+		if release.Draft || release.Prerelease {
+			continue
+		}
+		v, err := semver.NewVersion(release.TagName)
+		if err != nil || !constraint.Check(v) {
+			continue
+		}
+		if bestVersion == nil || v.GreaterThan(bestVersion) {
+			best, bestVersion = release, v
+		}
+	}
+	
+	if best == nil {
+		return nil, fmt.Errorf("%w: 仓库 %s/%s 中没有满足 %s 的Release", ErrNoRelease, owner, repo, version)
+	}
+	
+	c.logger.Info("按版本约束选择Release",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.String("constraint", version),
+		zap.String("tag", best.TagName),
+	)
+	
+	c.emit(Event{Type: EventReleaseResolved, Owner: owner, Repo: repo, Tag: best.TagName})
+	
+	return best, nil
+}
+
+// isVersionConstraint 检查版本描述是否为semver约束而不是具体的Tag
+func isVersionConstraint(version string) bool {
+	return strings.ContainsAny(version, "<>=~^*, ") || strings.HasSuffix(version, ".x")
+}
+
 // getSourceCodeURL 获取源代码URL
-func (c *Client) getSourceCodeURL(owner, repo, tag string) (string, error) {
+func (c *Client) getSourceCodeURL(ctx context.Context, owner, repo, tag string) (string, error) {
 	// 如果没有指定Tag，获取最新的Tag
 	if tag == "" {
-		release, err := c.getLatestRelease(owner, repo)
+		release, err := c.getLatestRelease(ctx, owner, repo)
 		if err != nil {
 			return "", err
 		}
-		tag = release.GetTagName()
+		tag = release.TagName
 	}
 	
 	// 构建源代码URL
-	// GitHub的源代码下载URL格式为: https://github.com/{owner}/{repo}/archive/refs/tags/{tag}.zip
-	url := fmt.Sprintf("https://github.com/%s/%s/archive/refs/tags/%s.zip", owner, repo, tag)
+	url := c.provider.SourceArchiveURL(owner, repo, tag)
 	
 	c.logger.Info("获取源代码URL成功",
 		zap.String("owner", owner),
@@ -97,13 +188,18 @@ func (c *Client) getSourceCodeURL(owner, repo, tag string) (string, error) {
 }
 
 // getLatestTagName 获取最新的Tag名称
-func (c *Client) getLatestTagName(owner, repo string) (string, error) {
-	release, err := c.getLatestRelease(owner, repo)
+func (c *Client) getLatestTagName(ctx context.Context, owner, repo string) (string, error) {
+	release, err := c.getLatestRelease(ctx, owner, repo)
 	if err != nil {
 		return "", err
 	}
 	
-	return release.GetTagName(), nil
+	return release.TagName, nil
+}
+
+// LatestVersion 获取最新版本的Tag
+func (c *Client) LatestVersion(owner, repo string) (string, error) {
+	return c.getLatestTagName(context.Background(), owner, repo)
 }
 
 // IsLatestVersion 检查当前版本是否为最新版本
@@ -115,7 +211,7 @@ func (c *Client) IsLatestVersion(owner, repo, currentVersion string) (bool, erro
 	)
 	
 	// 获取最新版本
-	latestVersion, err := c.getLatestTagName(owner, repo)
+	latestVersion, err := c.getLatestTagName(context.Background(), owner, repo)
 	if err != nil {
 		return false, err
 	}
@@ -139,11 +235,11 @@ func (c *Client) IsLatestVersion(owner, repo, currentVersion string) (bool, erro
 }
 
 // getReleaseAssets 获取Release的所有资产
-func (c *Client) getReleaseAssets(release *github.RepositoryRelease) []*github.ReleaseAsset {
+func (c *Client) getReleaseAssets(release *Release) []*Asset {
 	assets := release.Assets
 	
 	c.logger.Info("获取Release资产",
-		zap.String("tag", release.GetTagName()),
+		zap.String("tag", release.TagName),
 		zap.Int("assetCount", len(assets)),
 	)
 	
@@ -162,66 +258,13 @@ func (c *Client) getReleaseAssets(release *github.RepositoryRelease) []*github.R
 	)
 	
 	// 尝试找到匹配当前平台的资产
-	var matchedAssets []*github.ReleaseAsset
+	var matchedAssets []*Asset
 	
 	for _, asset := range assets {
-		name := asset.GetName()
+		name := asset.Name
 		lowerName := strings.ToLower(name)
 		
-			// 检查操作系统匹配
-		osMatched := false
-		archMatched := false
-		
-		// 操作系统匹配映射
-		osMap := map[string][]string{
-			"linux":   {"linux", "gnu", "gnulinux"},
-			"darwin":  {"darwin", "mac", "osx"},
-			"windows": {"windows", "win"},
-			"freebsd": {"freebsd", "bsd"},
-			"openbsd": {"openbsd", "bsd"},
-			"netbsd":  {"netbsd", "bsd"},
-		}
-		
-		// 架构匹配映射
-		archMap := map[string][]string{
-			"amd64":   {"amd64", "x86_64", "64bit"},
-			"386":     {"386", "i386", "x86", "32bit"},
-			"arm":     {"arm", "armv5", "armv6", "armv7"},
-			"arm64":   {"arm64", "aarch64"},
-			"mips":    {"mips"},
-			"mipsle":  {"mipsle", "mips32le"},
-			"mips64":  {"mips64"},
-			"mips64le": {"mips64le"},
-			"ppc64":   {"ppc64", "powerpc64"},
-			"ppc64le": {"ppc64le", "powerpc64le"},
-			"s390x":   {"s390x", "s390"},
-		}
-		
-		// 检查操作系统
-		if aliases, exists := osMap[currentOS]; exists {
-			for _, alias := range aliases {
-				if strings.Contains(lowerName, alias) {
-					osMatched = true
-					break
-				}
-			}
-		} else {
-			// 如果当前OS不在映射中，直接检查是否包含当前OS名称
-			osMatched = strings.Contains(lowerName, currentOS)
-		}
-		
-		// 检查架构
-		if aliases, exists := archMap[currentArch]; exists {
-			for _, alias := range aliases {
-				if strings.Contains(lowerName, alias) {
-					archMatched = true
-					break
-				}
-			}
-		} else {
-			// 如果当前架构不在映射中，直接检查是否包含当前架构名称
-			archMatched = strings.Contains(lowerName, currentArch)
-		}
+		osMatched, archMatched := matchPlatform(lowerName, currentOS, currentArch)
 		
 		// 如果操作系统和架构都匹配，添加到匹配列表
 		if osMatched && archMatched {
@@ -250,25 +293,119 @@ func (c *Client) getReleaseAssets(release *github.RepositoryRelease) []*github.R
 			zap.String("os", currentOS),
 			zap.String("arch", currentArch),
 		)
-		return []*github.ReleaseAsset{}
+		return []*Asset{}
 	}
 	
 	// 如果没有配置下载源代码，返回第一个资产
 	c.logger.Warn("没有找到匹配当前平台的资产，返回第一个资产",
 		zap.String("os", currentOS),
 		zap.String("arch", currentArch),
-		zap.String("assetName", assets[0].GetName()),
+		zap.String("assetName", assets[0].Name),
+	)
+	return []*Asset{assets[0]}
+}
+
+// matchPlatform 检查资产名称（小写）是否包含指定操作系统和架构的名称或别名
+func matchPlatform(lowerName, currentOS, currentArch string) (osMatched, archMatched bool) {
+	// 操作系统匹配映射
+	osMap := map[string][]string{
+		"linux":   {"linux", "gnu", "gnulinux"},
+		"darwin":  {"darwin", "mac", "osx"},
+		"windows": {"windows", "win"},
+		"freebsd": {"freebsd", "bsd"},
+		"openbsd": {"openbsd", "bsd"},
+		"netbsd":  {"netbsd", "bsd"},
+	}
+	
+	// 架构匹配映射
+	archMap := map[string][]string{
+		"amd64":   {"amd64", "x86_64", "64bit"},
+		"386":     {"386", "i386", "x86", "32bit"},
+		"arm":     {"arm", "armv5", "armv6", "armv7"},
+		"arm64":   {"arm64", "aarch64"},
+		"mips":    {"mips"},
+		"mipsle":  {"mipsle", "mips32le"},
+		"mips64":  {"mips64"},
+		"mips64le": {"mips64le"},
+		"ppc64":   {"ppc64", "powerpc64"},
+		"ppc64le": {"ppc64le", "powerpc64le"},
+		"s390x":   {"s390x", "s390"},
+	}
+	
+	// 检查操作系统
+	if aliases, exists := osMap[currentOS]; exists {
+		for _, alias := range aliases {
+			if strings.Contains(lowerName, alias) {
+				osMatched = true
+				break
+			}
+		}
+	} else {
+		// 如果当前OS不在映射中，直接检查是否包含当前OS名称
+		osMatched = strings.Contains(lowerName, currentOS)
+	}
+	
+	// 检查架构
+	if aliases, exists := archMap[currentArch]; exists {
+		for _, alias := range aliases {
+			if strings.Contains(lowerName, alias) {
+				archMatched = true
+				break
+			}
+		}
+	} else {
+		// 如果当前架构不在映射中，直接检查是否包含当前架构名称
+		archMatched = strings.Contains(lowerName, currentArch)
+	}
+	
+	return osMatched, archMatched
+}
+
+// selectAssets 选择要下载的资产
+// 设置了匹配模式时返回名称匹配该模式（glob）的资产，没有资产匹配时返回包装了 ErrNoMatchingAsset 的错误，
+// 不会回退到下载源代码；未设置匹配模式时按当前平台选择
+func (c *Client) selectAssets(release *Release, pattern string) ([]*Asset, error) {
+	if pattern == "" {
+		return c.getReleaseAssets(release), nil
+	}
+	if err := validateAssetPattern(pattern); err != nil {
+		return nil, err
+	}
+	
+	var matchedAssets []*Asset
+	for _, asset := range release.Assets {
+		if matched, _ := path.Match(pattern, asset.Name); matched {
+			matchedAssets = append(matchedAssets, asset)
+		}
+	}
+	
+	c.logger.Info("按模式匹配Release资产",
+		zap.String("tag", release.TagName),
+		zap.String("pattern", pattern),
+		zap.Int("matchedCount", len(matchedAssets)),
 	)
-	return []*github.ReleaseAsset{assets[0]}
+	
+	if len(matchedAssets) == 0 {
+		return nil, fmt.Errorf("Release %s 中没有名称匹配 %s 的资产: %w", release.TagName, pattern, ErrNoMatchingAsset)
+	}
+	
+	return matchedAssets, nil
+}
+
+// validateAssetPattern 检查资产匹配模式是否为有效的glob
+func validateAssetPattern(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("无效的资产匹配模式 %q: %w", pattern, err)
+	}
+	return nil
 }
 
 // getAssetDownloadURL 获取资产的下载URL
-func (c *Client) getAssetDownloadURL(asset *github.ReleaseAsset) string {
-	// 优先使用浏览器下载URL，这样可以避免API速率限制
-	url := asset.GetBrowserDownloadURL()
+func (c *Client) getAssetDownloadURL(asset *Asset) string {
+	url := c.provider.AssetDownloadURL(asset)
 	
 	c.logger.Debug("获取资产下载URL",
-		zap.String("name", asset.GetName()),
+		zap.String("name", asset.Name),
 		zap.String("url", url),
 	)
 	
@@ -276,7 +413,7 @@ func (c *Client) getAssetDownloadURL(asset *github.ReleaseAsset) string {
 }
 
 // shouldDownloadSource 检查是否应该下载源代码
-func (c *Client) shouldDownloadSource(assets []*github.ReleaseAsset) bool {
+func (c *Client) shouldDownloadSource(assets []*Asset) bool {
 	// 如果没有资产，且配置了下载源代码，则返回true
 	return len(assets) == 0 && c.options.DownloadSource
 }
\ No newline at end of file
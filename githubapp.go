@@ -0,0 +1,99 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/go-github/v76/github"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// githubAppJWTLifetime GitHub App JWT的有效期，GitHub允许的最大值为10分钟
+	githubAppJWTLifetime = 9 * time.Minute
+
+	// githubAppClockSkew 签发时间提前的时长，避免本机与GitHub的时钟偏差导致JWT尚未生效
+	githubAppClockSkew = time.Minute
+
+	// githubAppTokenEarlyExpiry 安装令牌提前刷新的时长，避免令牌在请求过程中过期
+	githubAppTokenEarlyExpiry = 5 * time.Minute
+)
+
+// githubAppJWTSource 使用GitHub App私钥签发JWT，用于以App身份调用API
+type githubAppJWTSource struct {
+	appID int64
+	key   *rsa.PrivateKey
+}
+
+// Token 签发新的JWT
+func (s *githubAppJWTSource) Token() (*oauth2.Token, error) {
+	now := time.Now()
+	expiresAt := now.Add(githubAppJWTLifetime)
+	claims := jwt.RegisteredClaims{
+		Issuer:    strconv.FormatInt(s.appID, 10),
+		IssuedAt:  jwt.NewNumericDate(now.Add(-githubAppClockSkew)),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(s.key)
+	if err != nil {
+		return nil, fmt.Errorf("签发GitHub App JWT失败: %w", err)
+	}
+
+	return &oauth2.Token{AccessToken: signed, TokenType: "Bearer", Expiry: expiresAt}, nil
+}
+
+// githubAppTokenSource 使用App的JWT换取安装令牌
+type githubAppTokenSource struct {
+	client         *github.Client // 使用JWT认证的GitHub客户端
+	installationID int64
+	timeout        time.Duration
+}
+
+// Token 获取新的安装令牌
+func (s *githubAppTokenSource) Token() (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(withMetricsEndpoint(context.Background(), "installation_token"), s.timeout)
+	defer cancel()
+
+	token, _, err := s.client.Apps.CreateInstallationToken(ctx, s.installationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("获取GitHub App安装令牌失败: %w", err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		TokenType:   "Bearer",
+		Expiry:      token.GetExpiresAt().Time,
+	}, nil
+}
+
+// newGitHubAppTokenSource 创建GitHub App安装令牌的TokenSource，令牌在过期前自动刷新
+func newGitHubAppTokenSource(httpClient *http.Client, options *Options) (oauth2.TokenSource, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(options.GitHubAppPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("解析GitHub App私钥失败: %w", err)
+	}
+
+	// JWT只用于换取安装令牌，同样使用共享的Transport以经过代理
+	jwtSource := oauth2.ReuseTokenSource(nil, &githubAppJWTSource{appID: options.GitHubAppID, key: key})
+	jwtClient := &http.Client{
+		Transport: &oauth2.Transport{Source: jwtSource, Base: httpClient.Transport},
+		Timeout:   httpClient.Timeout,
+	}
+
+	client, err := withEnterpriseURLs(github.NewClient(jwtClient), options)
+	if err != nil {
+		return nil, err
+	}
+
+	return oauth2.ReuseTokenSourceWithExpiry(nil, &githubAppTokenSource{
+		client:         client,
+		installationID: options.GitHubAppInstallationID,
+		timeout:        httpClient.Timeout,
+	}, githubAppTokenEarlyExpiry), nil
+}
@@ -0,0 +1,265 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v76/github"
+	"golang.org/x/oauth2"
+)
+
+// githubProvider 使用GitHub API获取Release
+type githubProvider struct {
+	client       *github.Client
+	webURL       string             // GitHub网页地址，以/结尾
+	tokenSource  oauth2.TokenSource // 访问令牌来源，为nil时匿名访问
+	privateRepos map[string]bool    // 标记为私有的仓库，键为小写的 owner/repo
+}
+
+// newGitHubProvider 创建GitHub的ReleaseProvider
+func newGitHubProvider(httpClient *http.Client, options *Options, tokenSource oauth2.TokenSource) (*githubProvider, error) {
+	client, err := createGitHubClient(httpClient, options, tokenSource)
+	if err != nil {
+		return nil, err
+	}
+
+	privateRepos := make(map[string]bool, len(options.PrivateRepos))
+	for _, repo := range options.PrivateRepos {
+		privateRepos[strings.ToLower(repo)] = true
+	}
+
+	return &githubProvider{
+		client:       client,
+		webURL:       githubWebURL(client.BaseURL),
+		tokenSource:  tokenSource,
+		privateRepos: privateRepos,
+	}, nil
+}
+
+// LatestRelease 获取最新的Release
+func (p *githubProvider) LatestRelease(ctx context.Context, owner, repo string) (*Release, error) {
+	release, resp, err := p.client.Repositories.GetLatestRelease(ctx, owner, repo)
+	if err != nil {
+		// 检查是否是因为没有Release
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("仓库 %s/%s %w", owner, repo, ErrNoRelease)
+		}
+		return nil, githubError(err)
+	}
+	return convertGitHubRelease(release), nil
+}
+
+// ReleaseByTag 通过Tag获取Release
+func (p *githubProvider) ReleaseByTag(ctx context.Context, owner, repo, tag string) (*Release, error) {
+	release, resp, err := p.client.Repositories.GetReleaseByTag(ctx, owner, repo, tag)
+	if err != nil {
+		// 检查是否是因为Tag不存在
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: 仓库 %s/%s 中没有Tag为 %s 的Release", ErrTagNotFound, owner, repo, tag)
+		}
+		return nil, githubError(err)
+	}
+	return convertGitHubRelease(release), nil
+}
+
+// ListReleases 分页获取仓库的所有Release
+func (p *githubProvider) ListReleases(ctx context.Context, owner, repo string) ([]*Release, error) {
+	var releases []*Release
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := p.client.Repositories.ListReleases(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, githubError(err)
+		}
+		for _, release := range page {
+			releases = append(releases, convertGitHubRelease(release))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return releases, nil
+}
+
+// AssetDownloadURL 获取资产的下载URL
+// 默认使用浏览器下载URL，不消耗API速率限制；通过 WithPrivateRepos 标记为私有的仓库直接通过API下载
+func (p *githubProvider) AssetDownloadURL(asset *Asset) string {
+	if owner, repo, ok := p.assetRepo(asset); ok && p.isPrivate(owner, repo) {
+		if apiURL, ok := p.AuthenticatedAssetURL(asset); ok {
+			return apiURL
+		}
+	}
+	return asset.BrowserDownloadURL
+}
+
+// SourceArchiveURL 获取源代码压缩包URL
+// 默认使用网页下载地址，通过 WithPrivateRepos 标记为私有的仓库直接通过API下载
+func (p *githubProvider) SourceArchiveURL(owner, repo, tag string) string {
+	if p.isPrivate(owner, repo) {
+		if apiURL, ok := p.AuthenticatedSourceArchiveURL(owner, repo, tag); ok {
+			return apiURL
+		}
+	}
+	// GitHub的源代码下载URL格式为: https://github.com/{owner}/{repo}/archive/refs/tags/{tag}.zip
+	// 连接GitHub Enterprise Server时使用企业版的网页地址
+	return fmt.Sprintf("%s%s/%s/archive/refs/tags/%s.zip", p.webURL, owner, repo, tag)
+}
+
+// AuthenticatedAssetURL 获取资产的API下载地址，私有仓库的浏览器下载URL无法使用令牌访问
+// 没有令牌时返回false；锁文件中的资产没有API地址，根据浏览器下载URL和资产ID构建
+func (p *githubProvider) AuthenticatedAssetURL(asset *Asset) (string, bool) {
+	if p.tokenSource == nil {
+		return "", false
+	}
+	if asset.APIURL != "" {
+		return asset.APIURL, true
+	}
+	owner, repo, ok := p.assetRepo(asset)
+	if !ok || asset.ID == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("%srepos/%s/%s/releases/assets/%d", p.client.BaseURL, owner, repo, asset.ID), true
+}
+
+// AuthenticatedSourceArchiveURL 获取源代码压缩包的API下载地址，没有令牌时返回false
+func (p *githubProvider) AuthenticatedSourceArchiveURL(owner, repo, tag string) (string, bool) {
+	if p.tokenSource == nil {
+		return "", false
+	}
+	return fmt.Sprintf("%srepos/%s/%s/zipball/refs/tags/%s", p.client.BaseURL, owner, repo, tag), true
+}
+
+// isPrivate 检查仓库是否通过 WithPrivateRepos 标记为私有
+func (p *githubProvider) isPrivate(owner, repo string) bool {
+	return p.privateRepos[strings.ToLower(owner+"/"+repo)]
+}
+
+// assetRepo 从浏览器下载URL中解析资产所属的仓库
+// 格式为 {webURL}{owner}/{repo}/releases/download/{tag}/{name}
+func (p *githubProvider) assetRepo(asset *Asset) (owner, repo string, ok bool) {
+	path, found := strings.CutPrefix(asset.BrowserDownloadURL, p.webURL)
+	if !found {
+		return "", "", false
+	}
+	parts := strings.SplitN(path, "/", 4)
+	if len(parts) < 4 || parts[2] != "releases" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// AuthorizeRequest 为指向GitHub API的下载请求添加访问令牌
+// API会重定向到带签名的存储地址，重定向到其他主机时Client会移除Authorization头
+// 只认证API地址，GitHub Enterprise Server的网页地址与API同一主机时同样不会带上令牌
+func (p *githubProvider) AuthorizeRequest(req *http.Request) error {
+	if !strings.HasPrefix(req.URL.String(), p.client.BaseURL.String()) {
+		return nil
+	}
+	token, err := bearerToken(p.tokenSource)
+	if err != nil || token == "" {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	// 资产API默认返回JSON描述，指定octet-stream时返回文件内容
+	if strings.Contains(req.URL.Path, "/releases/assets/") {
+		req.Header.Set("Accept", "application/octet-stream")
+	}
+	return nil
+}
+
+// convertGitHubRelease 将go-github的Release转换为Release
+func convertGitHubRelease(release *github.RepositoryRelease) *Release {
+	r := &Release{
+		TagName:     release.GetTagName(),
+		Name:        release.GetName(),
+		Body:        release.GetBody(),
+		Draft:       release.GetDraft(),
+		Prerelease:  release.GetPrerelease(),
+		HTMLURL:     release.GetHTMLURL(),
+		PublishedAt: release.GetPublishedAt().Time,
+	}
+	for _, asset := range release.Assets {
+		r.Assets = append(r.Assets, &Asset{
+			ID:                 asset.GetID(),
+			Name:               asset.GetName(),
+			Size:               int64(asset.GetSize()),
+			ContentType:        asset.GetContentType(),
+			BrowserDownloadURL: asset.GetBrowserDownloadURL(),
+			APIURL:             asset.GetURL(),
+		})
+	}
+	return r
+}
+
+// createGitHubClient 创建GitHub客户端，设置了企业版地址时连接GitHub Enterprise Server
+func createGitHubClient(httpClient *http.Client, options *Options, tokenSource oauth2.TokenSource) (*github.Client, error) {
+	var client *github.Client
+	if tokenSource != nil {
+		// 以共享的HTTP客户端作为底层，使认证请求同样经过代理
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+		tc := oauth2.NewClient(ctx, tokenSource)
+		client = github.NewClient(tc)
+	} else {
+		client = github.NewClient(httpClient)
+	}
+
+	return withEnterpriseURLs(client, options)
+}
+
+// withEnterpriseURLs 设置了企业版地址时将客户端指向GitHub Enterprise Server
+func withEnterpriseURLs(client *github.Client, options *Options) (*github.Client, error) {
+	if options.EnterpriseBaseURL == "" {
+		return client, nil
+	}
+
+	uploadURL := options.EnterpriseUploadURL
+	if uploadURL == "" {
+		uploadURL = options.EnterpriseBaseURL
+	}
+	return client.WithEnterpriseURLs(options.EnterpriseBaseURL, uploadURL)
+}
+
+// githubWebURL 根据API地址推导网页地址，用于构建源代码下载等网页链接
+// 例如 https://api.github.com/ 对应 https://github.com/，
+// https://ghe.example.com/api/v3/ 对应 https://ghe.example.com/
+func githubWebURL(apiURL *url.URL) string {
+	webURL := *apiURL
+	webURL.Host = strings.TrimPrefix(webURL.Host, "api.")
+	webURL.Path = strings.TrimSuffix(webURL.Path, "api/v3/")
+	if !strings.HasSuffix(webURL.Path, "/") {
+		webURL.Path += "/"
+	}
+	return webURL.String()
+}
+
+// githubError 将go-github的错误转换为 *RateLimitError 或 *HTTPStatusError，原始错误通过Unwrap保留
+func githubError(err error) error {
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	var respErr *github.ErrorResponse
+
+	switch {
+	case errors.As(err, &rateLimitErr):
+		return &RateLimitError{URL: responseURL(rateLimitErr.Response), Reset: rateLimitErr.Rate.Reset.Time, Err: err}
+	case errors.As(err, &abuseErr):
+		return &RateLimitError{URL: responseURL(abuseErr.Response), RetryAfter: abuseErr.GetRetryAfter(), Err: err}
+	case errors.As(err, &respErr) && respErr.Response != nil:
+		return &HTTPStatusError{Code: respErr.Response.StatusCode, URL: responseURL(respErr.Response), Err: err}
+	default:
+		return err
+	}
+}
+
+// responseURL 获取响应对应的请求地址
+func responseURL(resp *http.Response) string {
+	if resp == nil || resp.Request == nil {
+		return ""
+	}
+	return resp.Request.URL.String()
+}
@@ -0,0 +1,202 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// gitlabPageSize GitLab分页获取Release时每页的数量
+const gitlabPageSize = 100
+
+// gitlabProvider 使用GitLab API获取Release
+// GitLab的owner可以包含子组，例如 group/subgroup/project 对应 owner 为 group/subgroup
+type gitlabProvider struct {
+	httpClient  *http.Client
+	baseURL     *url.URL           // 实例地址，路径以/结尾
+	tokenSource oauth2.TokenSource // 访问令牌来源，为nil时匿名访问
+}
+
+// gitlabRelease GitLab API返回的Release
+type gitlabRelease struct {
+	TagName         string    `json:"tag_name"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	UpcomingRelease bool      `json:"upcoming_release"`
+	ReleasedAt      time.Time `json:"released_at"`
+	Links           struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+	Assets struct {
+		Links []gitlabAssetLink `json:"links"`
+	} `json:"assets"`
+}
+
+// gitlabAssetLink GitLab Release中的资产链接，通常指向通用软件包仓库或外部地址
+type gitlabAssetLink struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"`
+}
+
+// newGitLabProvider 创建GitLab的ReleaseProvider
+func newGitLabProvider(httpClient *http.Client, baseURL string, tokenSource oauth2.TokenSource) (*gitlabProvider, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("无效的GitLab地址: %q", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/"
+
+	return &gitlabProvider{
+		httpClient:  httpClient,
+		baseURL:     u,
+		tokenSource: tokenSource,
+	}, nil
+}
+
+// LatestRelease 获取最新的Release
+func (p *gitlabProvider) LatestRelease(ctx context.Context, owner, repo string) (*Release, error) {
+	var release gitlabRelease
+	found, err := p.get(ctx, p.projectAPIURL(owner, repo, "releases/permalink/latest"), &release)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("仓库 %s/%s %w", owner, repo, ErrNoRelease)
+	}
+	return release.convert(), nil
+}
+
+// ReleaseByTag 通过Tag获取Release
+func (p *gitlabProvider) ReleaseByTag(ctx context.Context, owner, repo, tag string) (*Release, error) {
+	var release gitlabRelease
+	found, err := p.get(ctx, p.projectAPIURL(owner, repo, "releases/"+url.PathEscape(tag)), &release)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: 仓库 %s/%s 中没有Tag为 %s 的Release", ErrTagNotFound, owner, repo, tag)
+	}
+	return release.convert(), nil
+}
+
+// ListReleases 分页获取仓库的所有Release
+func (p *gitlabProvider) ListReleases(ctx context.Context, owner, repo string) ([]*Release, error) {
+	var releases []*Release
+	for page := 1; ; page++ {
+		var pageReleases []gitlabRelease
+		endpoint := p.projectAPIURL(owner, repo, fmt.Sprintf("releases?page=%d&per_page=%d", page, gitlabPageSize))
+		found, err := p.get(ctx, endpoint, &pageReleases)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf("仓库 %s/%s 不存在", owner, repo)
+		}
+
+		for _, release := range pageReleases {
+			releases = append(releases, release.convert())
+		}
+
+		if len(pageReleases) < gitlabPageSize {
+			break
+		}
+	}
+	return releases, nil
+}
+
+// AssetDownloadURL 获取资产的下载URL
+func (p *gitlabProvider) AssetDownloadURL(asset *Asset) string {
+	return asset.BrowserDownloadURL
+}
+
+// SourceArchiveURL 获取源代码压缩包URL
+// GitLab的源代码下载URL格式为: {baseURL}/{owner}/{repo}/-/archive/{tag}/{repo}-{tag}.tar.gz
+func (p *gitlabProvider) SourceArchiveURL(owner, repo, tag string) string {
+	return fmt.Sprintf("%s%s/%s/-/archive/%s/%s-%s.tar.gz",
+		p.baseURL.String(), owner, repo, url.PathEscape(tag), repo, url.PathEscape(tag))
+}
+
+// AuthorizeRequest 为指向GitLab实例的下载请求添加访问令牌，私有项目的资产链接和源代码需要认证
+func (p *gitlabProvider) AuthorizeRequest(req *http.Request) error {
+	if req.URL.Host != p.baseURL.Host {
+		return nil
+	}
+	token, err := bearerToken(p.tokenSource)
+	if err != nil || token == "" {
+		return err
+	}
+	// 使用Authorization头，跨域重定向时net/http会自动移除，不会泄露给第三方
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// projectAPIURL 构建项目API地址，项目路径需要整体进行URL编码
+func (p *gitlabProvider) projectAPIURL(owner, repo, path string) string {
+	return fmt.Sprintf("%sapi/v4/projects/%s/%s", p.baseURL.String(), url.PathEscape(owner+"/"+repo), path)
+}
+
+// get 请求API并解析JSON响应，资源不存在时返回false
+func (p *gitlabProvider) get(ctx context.Context, endpoint string, v any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if err := p.AuthorizeRequest(req); err != nil {
+		return false, err
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("发送请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, responseError(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	return true, nil
+}
+
+// convert 将GitLab的Release转换为Release
+// 即将发布的Release（upcoming_release）视为预发布版本
+func (r *gitlabRelease) convert() *Release {
+	release := &Release{
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Description,
+		Prerelease:  r.UpcomingRelease,
+		HTMLURL:     r.Links.Self,
+		PublishedAt: r.ReleasedAt,
+	}
+	for _, link := range r.Assets.Links {
+		// 优先使用永久的直接下载地址
+		downloadURL := link.DirectAssetURL
+		if downloadURL == "" {
+			downloadURL = link.URL
+		}
+		release.Assets = append(release.Assets, &Asset{
+			ID:                 link.ID,
+			Name:               link.Name,
+			BrowserDownloadURL: downloadURL,
+			APIURL:             link.URL,
+		})
+	}
+	return release
+}
@@ -0,0 +1,336 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 安装目录中的特殊链接名称
+const (
+	currentLinkName  = "current"
+	previousLinkName = "previous"
+)
+
+// InstalledVersion 表示一个已安装的版本
+type InstalledVersion struct {
+	Tag         string    // 版本Tag
+	Path        string    // 安装目录
+	Active      bool      // 是否为当前激活的版本
+	InstalledAt time.Time // 安装时间
+}
+
+// Install 下载指定版本并安装到 <InstallDir>/<owner>/<repo>/<tag>/，然后激活该版本
+// 如果tag为空，安装最新版本
+func (c *Client) Install(owner, repo, tag string) (string, error) {
+	path, err := c.install(context.Background(), owner, repo, tag)
+	c.emitResult(owner, repo, path, err)
+	return path, err
+}
+
+// install 下载指定版本并安装到版本目录，然后激活该版本
+func (c *Client) install(ctx context.Context, owner, repo, tag string) (string, error) {
+	c.logger.Info("开始安装Release",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.String("tag", tag),
+	)
+
+	// 获取Release
+	var release *Release
+	var err error
+	if tag == "" {
+		release, err = c.getLatestRelease(ctx, owner, repo)
+	} else {
+		release, err = c.getReleaseByTag(ctx, owner, repo, tag)
+	}
+	if err != nil {
+		return "", err
+	}
+	tag = release.TagName
+	ctx = withEventScope(ctx, owner, repo, tag)
+
+	if err := validateInstallTag(tag); err != nil {
+		return "", err
+	}
+
+	// 如果该版本已安装，直接激活
+	versionDir := c.installVersionDir(owner, repo, tag)
+	if _, err := os.Stat(versionDir); err == nil {
+		c.logger.Info("该版本已安装，直接激活",
+			zap.String("owner", owner),
+			zap.String("repo", repo),
+			zap.String("tag", tag),
+		)
+		if err := c.Activate(owner, repo, tag); err != nil {
+			return "", err
+		}
+		return versionDir, nil
+	}
+
+	// 下载Release文件，没有匹配资产时下载源代码
+	var filePaths []string
+	assets := c.getReleaseAssets(release)
+	c.emitAssetsSelected(owner, repo, tag, assets)
+	if c.shouldDownloadSource(assets) {
+		url, err := c.getSourceCodeURL(ctx, owner, repo, tag)
+		if err != nil {
+			return "", err
+		}
+		filePath := filepath.Join(c.options.CacheDir, sourceFileName(owner, repo, tag, url))
+		if err := c.downloadSourceArchive(ctx, owner, repo, tag, url, filePath); err != nil {
+			return "", fmt.Errorf("下载源代码失败: %w", err)
+		}
+		filePaths = []string{filePath}
+	} else {
+		filePaths, err = c.downloadAssets(ctx, assets, c.releaseDir(owner, repo, tag))
+		if err != nil {
+			return "", err
+		}
+		// 部分资产下载失败时不安装，否则不完整的版本目录会被之后的安装直接激活
+		if len(filePaths) != len(assets) {
+			return "", fmt.Errorf("Release %s 中有 %d 个资产下载失败，已取消安装", tag, len(assets)-len(filePaths))
+		}
+	}
+
+	// 先在临时目录中组装，完成后再重命名为版本目录，避免留下半成品
+	stagingDir := filepath.Join(c.installRepoDir(owner, repo), "."+tag+".tmp")
+	if err := os.RemoveAll(stagingDir); err != nil {
+		return "", fmt.Errorf("清理临时安装目录失败: %w", err)
+	}
+	if err := os.MkdirAll(stagingDir, 0755); err != nil {
+		return "", fmt.Errorf("创建临时安装目录失败: %w", err)
+	}
+
+	for _, filePath := range filePaths {
+		// 压缩文件解压后再安装，解压失败时安装原文件
+		if isSupportedArchive(filePath) {
+			extractedPath, err := c.extractFile(ctx, filePath)
+			if err != nil {
+				c.logger.Warn("解压文件失败，安装原文件",
+					zap.String("filePath", filePath),
+					zap.Error(err),
+				)
+			} else {
+				filePath = extractedPath
+			}
+		} else if runtime.GOOS != "windows" {
+			// 非压缩文件通常是可执行文件
+			if err := os.Chmod(filePath, 0755); err != nil {
+				c.logger.Warn("设置文件权限失败",
+					zap.String("filePath", filePath),
+					zap.Error(err),
+				)
+			}
+		}
+
+		targetPath := filepath.Join(stagingDir, filepath.Base(filePath))
+		if err := c.moveFile(ctx, filePath, targetPath); err != nil {
+			os.RemoveAll(stagingDir)
+			return "", fmt.Errorf("移动文件到安装目录失败: %w", err)
+		}
+	}
+
+	if err := os.Rename(stagingDir, versionDir); err != nil {
+		os.RemoveAll(stagingDir)
+		return "", fmt.Errorf("创建版本目录失败: %w", err)
+	}
+
+	if err := c.Activate(owner, repo, tag); err != nil {
+		return "", err
+	}
+
+	c.logger.Info("Release安装成功",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.String("tag", tag),
+		zap.String("path", versionDir),
+	)
+
+	return versionDir, nil
+}
+
+// ListInstalled 列出已安装的所有版本，按安装时间排序
+func (c *Client) ListInstalled(owner, repo string) ([]InstalledVersion, error) {
+	repoDir := c.installRepoDir(owner, repo)
+
+	entries, err := os.ReadDir(repoDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取安装目录失败: %w", err)
+	}
+
+	current, _ := c.readInstallLink(owner, repo, currentLinkName)
+
+	var versions []InstalledVersion
+	for _, entry := range entries {
+		name := entry.Name()
+		// 跳过链接和临时目录
+		if !entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("获取版本目录信息失败: %w", err)
+		}
+
+		versions = append(versions, InstalledVersion{
+			Tag:         name,
+			Path:        filepath.Join(repoDir, name),
+			Active:      name == current,
+			InstalledAt: info.ModTime(),
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].InstalledAt.Before(versions[j].InstalledAt)
+	})
+
+	return versions, nil
+}
+
+// Activate 将current链接原子地切换到指定版本，原版本记录为previous以便回滚
+func (c *Client) Activate(owner, repo, tag string) error {
+	if err := validateInstallTag(tag); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(c.installVersionDir(owner, repo, tag)); err != nil {
+		return fmt.Errorf("版本 %s 未安装: %w", tag, err)
+	}
+
+	current, _ := c.readInstallLink(owner, repo, currentLinkName)
+	if current == tag {
+		return nil
+	}
+
+	if err := c.swapInstallLink(owner, repo, currentLinkName, tag); err != nil {
+		return fmt.Errorf("切换当前版本失败: %w", err)
+	}
+
+	if current != "" {
+		if err := c.swapInstallLink(owner, repo, previousLinkName, current); err != nil {
+			c.logger.Warn("记录上一版本失败",
+				zap.String("owner", owner),
+				zap.String("repo", repo),
+				zap.String("previous", current),
+				zap.Error(err),
+			)
+		}
+	}
+
+	c.logger.Info("已切换当前版本",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.String("from", current),
+		zap.String("to", tag),
+	)
+
+	return nil
+}
+
+// Rollback 回滚到上一次激活的版本，返回回滚后的版本Tag
+func (c *Client) Rollback(owner, repo string) (string, error) {
+	previous, err := c.readInstallLink(owner, repo, previousLinkName)
+	if err != nil || previous == "" {
+		return "", fmt.Errorf("仓库 %s/%s 没有可回滚的版本", owner, repo)
+	}
+
+	if err := c.Activate(owner, repo, previous); err != nil {
+		return "", err
+	}
+
+	return previous, nil
+}
+
+// Uninstall 删除指定的已安装版本，不允许删除当前激活的版本
+func (c *Client) Uninstall(owner, repo, tag string) error {
+	if err := validateInstallTag(tag); err != nil {
+		return err
+	}
+
+	if current, _ := c.readInstallLink(owner, repo, currentLinkName); current == tag {
+		return fmt.Errorf("不能卸载当前激活的版本 %s", tag)
+	}
+
+	versionDir := c.installVersionDir(owner, repo, tag)
+	if _, err := os.Stat(versionDir); err != nil {
+		return fmt.Errorf("版本 %s 未安装: %w", tag, err)
+	}
+
+	if err := os.RemoveAll(versionDir); err != nil {
+		return fmt.Errorf("删除版本目录失败: %w", err)
+	}
+
+	// 如果previous指向被删除的版本，一并删除
+	if previous, _ := c.readInstallLink(owner, repo, previousLinkName); previous == tag {
+		os.Remove(filepath.Join(c.installRepoDir(owner, repo), previousLinkName))
+	}
+
+	c.logger.Info("已卸载版本",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.String("tag", tag),
+	)
+
+	return nil
+}
+
+// installRepoDir 获取仓库的安装目录
+func (c *Client) installRepoDir(owner, repo string) string {
+	return filepath.Join(c.options.InstallDir, owner, repo)
+}
+
+// installVersionDir 获取指定版本的安装目录
+func (c *Client) installVersionDir(owner, repo, tag string) string {
+	return filepath.Join(c.installRepoDir(owner, repo), tag)
+}
+
+// readInstallLink 读取安装目录中链接指向的版本Tag
+func (c *Client) readInstallLink(owner, repo, name string) (string, error) {
+	target, err := os.Readlink(filepath.Join(c.installRepoDir(owner, repo), name))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Base(target), nil
+}
+
+// swapInstallLink 通过创建临时链接再重命名的方式原子地更新链接
+func (c *Client) swapInstallLink(owner, repo, name, tag string) error {
+	repoDir := c.installRepoDir(owner, repo)
+	tmpLink := filepath.Join(repoDir, "."+name+".tmp")
+
+	os.Remove(tmpLink)
+	if err := os.Symlink(tag, tmpLink); err != nil {
+		return fmt.Errorf("创建链接失败: %w", err)
+	}
+
+	if err := os.Rename(tmpLink, filepath.Join(repoDir, name)); err != nil {
+		os.Remove(tmpLink)
+		return fmt.Errorf("替换链接失败: %w", err)
+	}
+
+	return nil
+}
+
+// validateInstallTag 检查Tag是否可以作为安装目录名
+func validateInstallTag(tag string) error {
+	switch {
+	case tag == "", tag == ".", tag == "..",
+		tag == currentLinkName, tag == previousLinkName,
+		strings.HasPrefix(tag, "."),
+		strings.ContainsAny(tag, `/\`):
+		return fmt.Errorf("无效的版本Tag: %q", tag)
+	}
+	return nil
+}
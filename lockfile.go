@@ -0,0 +1,314 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// lockfileVersion 锁文件格式版本
+const lockfileVersion = 1
+
+// Lockfile 记录每个仓库锁定的版本和文件，用于可重复的下载
+type Lockfile struct {
+	Version int         `json:"version"`
+	Entries []LockEntry `json:"entries"`
+}
+
+// LockEntry 记录一个仓库锁定的版本
+type LockEntry struct {
+	Owner  string        `json:"owner"`
+	Repo   string        `json:"repo"`
+	Tag    string        `json:"tag"`
+	Assets []LockedAsset `json:"assets,omitempty"`
+	Source *LockedAsset  `json:"source,omitempty"` // 源代码压缩包
+}
+
+// LockedAsset 记录一个锁定的文件
+type LockedAsset struct {
+	ID     int64  `json:"id,omitempty"`
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// LoadLockfile 读取锁文件
+func LoadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取锁文件失败: %w", err)
+	}
+
+	var lockfile Lockfile
+	if err := json.Unmarshal(data, &lockfile); err != nil {
+		return nil, fmt.Errorf("解析锁文件失败: %w", err)
+	}
+
+	if lockfile.Version > lockfileVersion {
+		return nil, fmt.Errorf("不支持的锁文件版本: %d", lockfile.Version)
+	}
+
+	return &lockfile, nil
+}
+
+// Save 将锁文件原子地写入指定路径，条目按仓库排序以保证内容稳定
+func (l *Lockfile) Save(path string) error {
+	l.Version = lockfileVersion
+	sort.Slice(l.Entries, func(i, j int) bool {
+		return lockKey(l.Entries[i].Owner, l.Entries[i].Repo) < lockKey(l.Entries[j].Owner, l.Entries[j].Repo)
+	})
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化锁文件失败: %w", err)
+	}
+	data = append(data, '\n')
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("写入锁文件失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("写入锁文件失败: %w", err)
+	}
+
+	return nil
+}
+
+// Find 查找仓库锁定的版本，没有记录时返回nil
+func (l *Lockfile) Find(owner, repo string) *LockEntry {
+	key := lockKey(owner, repo)
+	for i := range l.Entries {
+		if lockKey(l.Entries[i].Owner, l.Entries[i].Repo) == key {
+			return &l.Entries[i]
+		}
+	}
+	return nil
+}
+
+// upsert 更新仓库锁定的版本，版本变化时清空原有记录
+func (l *Lockfile) upsert(owner, repo, tag string) *LockEntry {
+	entry := l.Find(owner, repo)
+	if entry == nil {
+		l.Entries = append(l.Entries, LockEntry{Owner: owner, Repo: repo, Tag: tag})
+		return &l.Entries[len(l.Entries)-1]
+	}
+	if entry.Tag != tag {
+		*entry = LockEntry{Owner: owner, Repo: repo, Tag: tag}
+	}
+	return entry
+}
+
+// lockKey 生成仓库的锁文件键，仓库名不区分大小写
+func lockKey(owner, repo string) string {
+	return strings.ToLower(owner + "/" + repo)
+}
+
+// loadClientLockfile 加载Client使用的锁文件，锁定模式下锁文件必须存在
+func loadClientLockfile(options *Options) (*Lockfile, error) {
+	if options.Lockfile == "" {
+		if options.FrozenLockfile {
+			return nil, fmt.Errorf("锁定模式需要设置锁文件")
+		}
+		return nil, nil
+	}
+
+	lockfile, err := LoadLockfile(options.Lockfile)
+	if err == nil {
+		return lockfile, nil
+	}
+	if options.FrozenLockfile || !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	// 锁文件不存在时创建新的锁文件
+	return &Lockfile{Version: lockfileVersion}, nil
+}
+
+// lockAssets 将下载的资产及其SHA-256记录到锁文件
+func (c *Client) lockAssets(owner, repo, tag string, assets []*Asset, files []DownloadedFile) error {
+	assetIDs := make(map[string]int64, len(assets))
+	for _, asset := range assets {
+		assetIDs[asset.Name] = asset.ID
+	}
+
+	locked := make([]LockedAsset, 0, len(files))
+	for _, file := range files {
+		locked = append(locked, LockedAsset{
+			ID:     assetIDs[file.Name],
+			Name:   file.Name,
+			URL:    file.URL,
+			Size:   file.Size,
+			SHA256: file.SHA256,
+		})
+	}
+
+	c.lockMu.Lock()
+	defer c.lockMu.Unlock()
+
+	entry := c.lockfile.upsert(owner, repo, tag)
+	entry.Assets = locked
+
+	return c.saveLockfile()
+}
+
+// lockSource 将下载的源代码压缩包及其SHA-256记录到锁文件
+func (c *Client) lockSource(owner, repo, tag string, file DownloadedFile) error {
+	c.lockMu.Lock()
+	defer c.lockMu.Unlock()
+
+	entry := c.lockfile.upsert(owner, repo, tag)
+	entry.Source = &LockedAsset{
+		Name:   file.Name,
+		URL:    file.URL,
+		Size:   file.Size,
+		SHA256: file.SHA256,
+	}
+
+	return c.saveLockfile()
+}
+
+// lockCachedResult 使用缓存的下载结果时，锁文件中没有该仓库或锁定的是其他版本则记录缓存的文件
+func (c *Client) lockCachedResult(cached *DownloadResult, release *Release) error {
+	c.lockMu.Lock()
+	entry := c.lockfile.Find(cached.Owner, cached.Repo)
+	locked := entry != nil && entry.Tag == cached.Tag
+	c.lockMu.Unlock()
+	if locked || len(cached.Files) == 0 {
+		return nil
+	}
+
+	if cached.Source {
+		return c.lockSource(cached.Owner, cached.Repo, cached.Tag, cached.Files[0])
+	}
+	return c.lockAssets(cached.Owner, cached.Repo, cached.Tag, release.Assets, cached.Files)
+}
+
+// saveLockfile 保存锁文件，调用方需要持有lockMu
+func (c *Client) saveLockfile() error {
+	if err := c.lockfile.Save(c.options.Lockfile); err != nil {
+		return err
+	}
+
+	c.logger.Debug("锁文件已更新", zap.String("path", c.options.Lockfile))
+
+	return nil
+}
+
+// findLockEntry 查找锁定的版本，tag不为空时必须与锁定的版本一致
+func (c *Client) findLockEntry(owner, repo, tag string) (*LockEntry, error) {
+	entry := c.lockfile.Find(owner, repo)
+	if entry == nil {
+		return nil, fmt.Errorf("锁文件中没有仓库 %s/%s 的记录", owner, repo)
+	}
+	if tag != "" && entry.Tag != tag {
+		return nil, fmt.Errorf("锁文件中仓库 %s/%s 的版本为 %s，与请求的版本 %s 不一致", owner, repo, entry.Tag, tag)
+	}
+	return entry, nil
+}
+
+// downloadLocked 下载锁文件中记录的资产并校验SHA-256
+// 如果锁定的是源代码，则下载源代码
+func (c *Client) downloadLocked(ctx context.Context, owner, repo, tag string, fo fetchOptions) (*DownloadResult, error) {
+	entry, err := c.findLockEntry(owner, repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withEventScope(ctx, owner, repo, entry.Tag)
+
+	if len(entry.Assets) == 0 {
+		if entry.Source != nil {
+			return c.downloadLockedSource(ctx, owner, repo, entry.Tag, fo)
+		}
+		return nil, fmt.Errorf("锁文件中仓库 %s/%s 没有记录文件", owner, repo)
+	}
+
+	c.logger.Info("按锁文件下载Release",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.String("tag", entry.Tag),
+		zap.Int("assetCount", len(entry.Assets)),
+	)
+
+	// 使用锁定的URL构建资产，复用并发下载
+	assets := make([]*Asset, 0, len(entry.Assets))
+	for _, locked := range entry.Assets {
+		assets = append(assets, &Asset{
+			ID:                 locked.ID,
+			Name:               locked.Name,
+			Size:               locked.Size,
+			BrowserDownloadURL: locked.URL,
+		})
+	}
+
+	files, err := c.downloadAssetFiles(ctx, assets, c.releaseDir(owner, repo, entry.Tag))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) != len(entry.Assets) {
+		return nil, fmt.Errorf("锁文件中仓库 %s/%s 的部分文件下载失败", owner, repo)
+	}
+
+	for i, locked := range entry.Assets {
+		if err := verifyLockedFile(files[i], locked); err != nil {
+			return nil, err
+		}
+		c.emit(Event{Type: EventChecksumVerified, Owner: owner, Repo: repo, Tag: entry.Tag, Asset: locked.Name, Path: files[i].Path})
+	}
+
+	result := &DownloadResult{Owner: owner, Repo: repo, Tag: entry.Tag, Files: files}
+	if err := c.finalizeFiles(ctx, result, fo); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// downloadLockedSource 下载锁文件中记录的源代码并校验SHA-256
+func (c *Client) downloadLockedSource(ctx context.Context, owner, repo, tag string, fo fetchOptions) (*DownloadResult, error) {
+	entry, err := c.findLockEntry(owner, repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Source == nil {
+		return nil, fmt.Errorf("锁文件中仓库 %s/%s 没有记录源代码", owner, repo)
+	}
+	ctx = withEventScope(ctx, owner, repo, entry.Tag)
+
+	filePath := filepath.Join(c.options.CacheDir, entry.Source.Name)
+	if err := c.downloadSourceArchive(ctx, owner, repo, entry.Tag, entry.Source.URL, filePath); err != nil {
+		return nil, fmt.Errorf("下载源代码失败: %w", err)
+	}
+
+	file, err := newDownloadedFile(entry.Source.URL, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyLockedFile(file, *entry.Source); err != nil {
+		return nil, err
+	}
+	c.emit(Event{Type: EventChecksumVerified, Owner: owner, Repo: repo, Tag: entry.Tag, Asset: entry.Source.Name, Path: filePath})
+
+	result := &DownloadResult{Owner: owner, Repo: repo, Tag: entry.Tag, Files: []DownloadedFile{file}, Source: true}
+	result.Path = c.finalizeFile(ctx, &result.Files[0], fo)
+	return result, nil
+}
+
+// verifyLockedFile 校验下载的文件与锁文件记录的SHA-256是否一致，不一致时删除文件
+func verifyLockedFile(file DownloadedFile, locked LockedAsset) error {
+	if !strings.EqualFold(file.SHA256, locked.SHA256) {
+		os.Remove(file.Path)
+		return fmt.Errorf("文件与锁文件不一致: %w", &ChecksumMismatchError{Name: locked.Name, Expected: locked.SHA256, Actual: file.SHA256})
+	}
+
+	return nil
+}
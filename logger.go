@@ -0,0 +1,138 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"log/slog"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// setupLogger 设置日志
+// 优先使用 WithLogger，其次将日志转发给 WithSlogHandler，设置了 WithLoggerLevel 时使用内置的JSON日志，否则不输出日志
+func setupLogger(options *Options) (*zap.Logger, error) {
+	switch {
+	case options.Logger != nil:
+		return options.Logger, nil
+	case options.SlogHandler != nil:
+		return zap.New(&slogCore{handler: options.SlogHandler}, zap.AddCaller()), nil
+	case options.LoggerLevel != "":
+		return newJSONLogger(options.LoggerLevel)
+	default:
+		return zap.NewNop(), nil
+	}
+}
+
+// newJSONLogger 创建输出到标准错误的JSON日志，避免干扰命令行工具的标准输出
+func newJSONLogger(level string) (*zap.Logger, error) {
+	var zapLevel zapcore.Level
+	switch level {
+	case "debug":
+		zapLevel = zapcore.DebugLevel
+	case "info":
+		zapLevel = zapcore.InfoLevel
+	case "warn":
+		zapLevel = zapcore.WarnLevel
+	case "error":
+		zapLevel = zapcore.ErrorLevel
+	default:
+		zapLevel = zapcore.InfoLevel
+	}
+
+	config := zap.Config{
+		Level:       zap.NewAtomicLevelAt(zapLevel),
+		Development: false,
+		Encoding:    "json",
+		EncoderConfig: zapcore.EncoderConfig{
+			TimeKey:        "time",
+			LevelKey:       "level",
+			NameKey:        "logger",
+			CallerKey:      "caller",
+			FunctionKey:    zapcore.OmitKey,
+			MessageKey:     "msg",
+			StacktraceKey:  "stacktrace",
+			LineEnding:     zapcore.DefaultLineEnding,
+			EncodeLevel:    zapcore.LowercaseLevelEncoder,
+			EncodeTime:     zapcore.ISO8601TimeEncoder,
+			EncodeDuration: zapcore.SecondsDurationEncoder,
+			EncodeCaller:   zapcore.ShortCallerEncoder,
+		},
+		OutputPaths:      []string{"stderr"},
+		ErrorOutputPaths: []string{"stderr"},
+	}
+
+	return config.Build()
+}
+
+// slogCore 将zap的日志转发给slog处理器
+type slogCore struct {
+	handler slog.Handler
+}
+
+// Enabled 由slog处理器决定是否输出该级别
+func (c *slogCore) Enabled(level zapcore.Level) bool {
+	return c.handler.Enabled(context.Background(), slogLevel(level))
+}
+
+// With 添加公共字段
+func (c *slogCore) With(fields []zapcore.Field) zapcore.Core {
+	return &slogCore{handler: c.handler.WithAttrs(slogAttrs(fields))}
+}
+
+// Check 级别启用时将自身加入待写入的Core
+func (c *slogCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write 将日志转换为slog记录并交给处理器
+func (c *slogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	var pc uintptr
+	if entry.Caller.Defined {
+		pc = entry.Caller.PC
+	}
+
+	record := slog.NewRecord(entry.Time, slogLevel(entry.Level), entry.Message, pc)
+	record.AddAttrs(slogAttrs(fields)...)
+	if entry.LoggerName != "" {
+		record.AddAttrs(slog.String("logger", entry.LoggerName))
+	}
+	return c.handler.Handle(context.Background(), record)
+}
+
+// Sync slog处理器没有缓冲，无需同步
+func (c *slogCore) Sync() error {
+	return nil
+}
+
+// slogLevel 将zap的日志级别转换为slog的级别
+func slogLevel(level zapcore.Level) slog.Level {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return slog.LevelDebug
+	case level == zapcore.InfoLevel:
+		return slog.LevelInfo
+	case level == zapcore.WarnLevel:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// slogAttrs 将zap的字段转换为slog的属性
+func slogAttrs(fields []zapcore.Field) []slog.Attr {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, field := range fields {
+		if value, ok := encoder.Fields[field.Key]; ok {
+			attrs = append(attrs, slog.Any(field.Key, value))
+		}
+	}
+	return attrs
+}
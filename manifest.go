@@ -0,0 +1,121 @@
+package githubreleasedownloader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest 描述一组需要同步的工具
+type Manifest struct {
+	Tools []ManifestEntry `yaml:"tools" toml:"tools"`
+}
+
+// ManifestEntry 描述清单中的一个工具
+type ManifestEntry struct {
+	Repo    string `yaml:"repo" toml:"repo"`       // 仓库，格式为 owner/repo
+	Version string `yaml:"version" toml:"version"` // 版本：为空或latest表示最新版本，也可以是Tag或semver约束
+	Asset   string `yaml:"asset" toml:"asset"`     // 资产名匹配模式（glob），为空时按当前平台选择；读取清单时检查模式是否有效
+	Extract *bool  `yaml:"extract" toml:"extract"` // 是否解压，为空时使用Client的配置
+	Dest    string `yaml:"dest" toml:"dest"`       // 目标目录，相对路径基于清单文件所在目录，为空时使用Client的配置
+}
+
+// ManifestResult 表示清单中一个工具的同步结果
+type ManifestResult struct {
+	Repo   string          // 仓库
+	Result *DownloadResult // 下载结果，失败时为nil
+	Err    error           // 同步失败时的错误
+}
+
+// LoadManifest 读取清单文件，根据扩展名识别YAML（.yaml、.yml）或TOML（.toml）格式
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取清单文件失败: %w", err)
+	}
+
+	var manifest Manifest
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &manifest)
+	case ".toml":
+		err = toml.Unmarshal(data, &manifest)
+	default:
+		return nil, fmt.Errorf("不支持的清单格式: %s", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析清单文件失败: %w", err)
+	}
+
+	// 相对的目标目录基于清单文件所在目录
+	baseDir := filepath.Dir(path)
+	for i, entry := range manifest.Tools {
+		if _, _, err := splitRepo(entry.Repo); err != nil {
+			return nil, fmt.Errorf("清单第 %d 项: %w", i+1, err)
+		}
+		if entry.Asset != "" {
+			if err := validateAssetPattern(entry.Asset); err != nil {
+				return nil, fmt.Errorf("清单第 %d 项: %w", i+1, err)
+			}
+		}
+		if entry.Dest != "" && !filepath.IsAbs(entry.Dest) {
+			manifest.Tools[i].Dest = filepath.Join(baseDir, entry.Dest)
+		}
+	}
+
+	return &manifest, nil
+}
+
+// SyncManifest 读取清单文件并通过 DownloadBatch 下载其中的所有工具，opts与 DownloadBatch 相同
+// 返回每一项的同步结果，有条目失败时同时返回错误
+func (c *Client) SyncManifest(path string, opts BatchOptions) ([]ManifestResult, error) {
+	manifest, err := LoadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("开始同步清单",
+		zap.String("path", path),
+		zap.Int("toolCount", len(manifest.Tools)),
+	)
+
+	requests := make([]ReleaseRequest, 0, len(manifest.Tools))
+	for _, entry := range manifest.Tools {
+		owner, repo, _ := splitRepo(entry.Repo)
+		requests = append(requests, ReleaseRequest{
+			Owner:        owner,
+			Repo:         repo,
+			Version:      entry.Version,
+			AssetPattern: entry.Asset,
+			AutoExtract:  entry.Extract,
+			TargetDir:    entry.Dest,
+		})
+	}
+
+	batchResults, err := c.DownloadBatch(requests, opts)
+
+	results := make([]ManifestResult, len(batchResults))
+	for i, result := range batchResults {
+		results[i] = ManifestResult{
+			Repo:   manifest.Tools[i].Repo,
+			Result: result.Result,
+			Err:    result.Err,
+		}
+	}
+
+	return results, err
+}
+
+// splitRepo 将 owner/repo 拆分为owner和repo
+func splitRepo(s string) (string, string, error) {
+	idx := strings.LastIndex(s, "/")
+	if idx <= 0 || idx == len(s)-1 {
+		return "", "", fmt.Errorf("无效的仓库 %q，格式应为 owner/repo", s)
+	}
+	return s[:idx], s[idx+1:], nil
+}
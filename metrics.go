@@ -0,0 +1,94 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// MetricsRecorder 记录下载和API调用的指标，通过 WithMetrics 设置
+// Prometheus的实现见 metrics 子包，核心包不依赖任何指标库。实现需要支持并发调用
+type MetricsRecorder interface {
+	// ObserveAPIRequest 记录一次API请求，status为HTTP状态码或 error
+	ObserveAPIRequest(endpoint, status string)
+
+	// ObserveDownload 记录一次文件下载的结果和耗时
+	ObserveDownload(duration time.Duration, err error)
+
+	// AddDownloadBytes 累加下载的字节数
+	AddDownloadBytes(n int64)
+
+	// ObserveCache 记录下载最新版本时的一次缓存命中或未命中
+	ObserveCache(hit bool)
+
+	// IncExtractFailures 记录一次解压失败
+	IncExtractFailures()
+
+	// SetRateLimitRemaining 记录主机最近一次响应中速率限制的剩余请求数
+	SetRateLimitRemaining(host string, remaining float64)
+}
+
+// noopMetrics 未设置 WithMetrics 时使用，不记录任何指标
+type noopMetrics struct{}
+
+// ObserveAPIRequest 实现 MetricsRecorder
+func (noopMetrics) ObserveAPIRequest(string, string) {}
+
+// ObserveDownload 实现 MetricsRecorder
+func (noopMetrics) ObserveDownload(time.Duration, error) {}
+
+// AddDownloadBytes 实现 MetricsRecorder
+func (noopMetrics) AddDownloadBytes(int64) {}
+
+// ObserveCache 实现 MetricsRecorder
+func (noopMetrics) ObserveCache(bool) {}
+
+// IncExtractFailures 实现 MetricsRecorder
+func (noopMetrics) IncExtractFailures() {}
+
+// SetRateLimitRemaining 实现 MetricsRecorder
+func (noopMetrics) SetRateLimitRemaining(string, float64) {}
+
+// newMetricsRecorder 返回配置的指标记录器，未设置时返回不记录指标的实现
+func newMetricsRecorder(options *Options) MetricsRecorder {
+	if options.Metrics == nil {
+		return noopMetrics{}
+	}
+	return options.Metrics
+}
+
+// metricsEndpointKey 在context中保存API端点名称的键
+type metricsEndpointKey struct{}
+
+// withMetricsEndpoint 标记请求对应的API端点，只有标记过的请求才计入API调用次数
+func withMetricsEndpoint(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, metricsEndpointKey{}, endpoint)
+}
+
+// metricsTransport 记录API调用次数和速率限制剩余次数的Transport
+type metricsTransport struct {
+	base    http.RoundTripper
+	metrics MetricsRecorder
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+
+	if endpoint, ok := req.Context().Value(metricsEndpointKey{}).(string); ok {
+		status := "error"
+		if err == nil {
+			status = strconv.Itoa(resp.StatusCode)
+		}
+		t.metrics.ObserveAPIRequest(endpoint, status)
+	}
+
+	if err == nil {
+		if remaining, parseErr := strconv.ParseFloat(rateLimitHeader(resp.Header, "Remaining"), 64); parseErr == nil {
+			t.metrics.SetRateLimitRemaining(req.URL.Hostname(), remaining)
+		}
+	}
+
+	return resp, err
+}
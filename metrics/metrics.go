@@ -0,0 +1,150 @@
+// Package metrics 提供 github-release-downloader 的Prometheus指标收集器
+// 核心包只依赖 MetricsRecorder 接口，不使用指标时不会引入Prometheus的依赖
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	githubreleasedownloader "github.com/sunwu57/github-release-downloader"
+)
+
+// namespace 指标名称的前缀
+const namespace = "grd"
+
+// 下载结果的标签值
+const (
+	downloadResultSuccess = "success"
+	downloadResultFailure = "failure"
+)
+
+var _ githubreleasedownloader.MetricsRecorder = (*Metrics)(nil)
+
+// Metrics 收集下载和API调用的Prometheus指标，实现了 prometheus.Collector 和 githubreleasedownloader.MetricsRecorder
+// 通过 WithMetrics 传给Client，由调用方自行注册到Registry；多个Client可以共享同一个Metrics
+type Metrics struct {
+	apiRequests        *prometheus.CounterVec
+	downloads          *prometheus.CounterVec
+	downloadBytes      prometheus.Counter
+	downloadDuration   prometheus.Histogram
+	cacheHits          prometheus.Counter
+	cacheMisses        prometheus.Counter
+	extractFailures    prometheus.Counter
+	rateLimitRemaining *prometheus.GaugeVec
+}
+
+// New 创建指标收集器
+func New() *Metrics {
+	return &Metrics{
+		apiRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_requests_total",
+			Help:      "按端点和HTTP状态码统计的API请求数",
+		}, []string{"endpoint", "status"}),
+		downloads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "downloads_total",
+			Help:      "按结果统计的文件下载数",
+		}, []string{"result"}),
+		downloadBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "download_bytes_total",
+			Help:      "下载的总字节数",
+		}),
+		downloadDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "download_duration_seconds",
+			Help:      "文件下载耗时（包括镜像切换）",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 14),
+		}),
+		cacheHits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_hits_total",
+			Help:      "下载最新版本时命中缓存的次数",
+		}),
+		cacheMisses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_misses_total",
+			Help:      "下载最新版本时未命中缓存的次数",
+		}),
+		extractFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "extract_failures_total",
+			Help:      "解压失败的次数",
+		}),
+		rateLimitRemaining: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "rate_limit_remaining",
+			Help:      "按主机统计的当前速率限制窗口内剩余的请求数",
+		}, []string{"host"}),
+	}
+}
+
+// Describe 实现 prometheus.Collector
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, collector := range m.collectors() {
+		collector.Describe(ch)
+	}
+}
+
+// Collect 实现 prometheus.Collector
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	for _, collector := range m.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+// collectors 返回所有指标
+func (m *Metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.apiRequests,
+		m.downloads,
+		m.downloadBytes,
+		m.downloadDuration,
+		m.cacheHits,
+		m.cacheMisses,
+		m.extractFailures,
+		m.rateLimitRemaining,
+	}
+}
+
+// ObserveAPIRequest 实现 githubreleasedownloader.MetricsRecorder
+func (m *Metrics) ObserveAPIRequest(endpoint, status string) {
+	m.apiRequests.WithLabelValues(endpoint, status).Inc()
+}
+
+// ObserveDownload 实现 githubreleasedownloader.MetricsRecorder
+func (m *Metrics) ObserveDownload(duration time.Duration, err error) {
+	result := downloadResultSuccess
+	if err != nil {
+		result = downloadResultFailure
+	}
+	m.downloads.WithLabelValues(result).Inc()
+	m.downloadDuration.Observe(duration.Seconds())
+}
+
+// AddDownloadBytes 实现 githubreleasedownloader.MetricsRecorder
+func (m *Metrics) AddDownloadBytes(n int64) {
+	if n > 0 {
+		m.downloadBytes.Add(float64(n))
+	}
+}
+
+// ObserveCache 实现 githubreleasedownloader.MetricsRecorder
+func (m *Metrics) ObserveCache(hit bool) {
+	if hit {
+		m.cacheHits.Inc()
+	} else {
+		m.cacheMisses.Inc()
+	}
+}
+
+// IncExtractFailures 实现 githubreleasedownloader.MetricsRecorder
+func (m *Metrics) IncExtractFailures() {
+	m.extractFailures.Inc()
+}
+
+// SetRateLimitRemaining 实现 githubreleasedownloader.MetricsRecorder
+func (m *Metrics) SetRateLimitRemaining(host string, remaining float64) {
+	m.rateLimitRemaining.WithLabelValues(host).Set(remaining)
+}
@@ -0,0 +1,153 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// mirrorProbeTimeout 探测单个镜像的超时时间
+const mirrorProbeTimeout = 5 * time.Second
+
+// Mirror 描述一条镜像规则，用于改写资产和源代码的下载地址
+// 根据 From 的形式有三种规则：
+//   - From 为空：前缀镜像（ghproxy风格），镜像地址为 To + 原始URL，例如 To 为 https://ghproxy.net/
+//   - From 为主机名（如 github.com）：替换主机，To 为新的主机名（可以包含端口），协议和路径不变
+//   - From 为URL前缀（如 https://github.com/）：替换前缀，例如 To 为 https://artifactory.example.com/artifactory/github/
+type Mirror struct {
+	From string // 匹配的主机名或URL前缀，为空时匹配所有地址
+	To   string // 镜像前缀、主机名或替换后的URL前缀
+}
+
+// Rewrite 使用镜像规则改写地址，规则不匹配时返回false
+func (m Mirror) Rewrite(rawURL string) (string, bool) {
+	switch {
+	case m.To == "":
+		return "", false
+	case m.From == "":
+		return m.To + rawURL, true
+	case strings.Contains(m.From, "://"):
+		if !strings.HasPrefix(rawURL, m.From) {
+			return "", false
+		}
+		return m.To + strings.TrimPrefix(rawURL, m.From), true
+	default:
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Host != m.From {
+			return "", false
+		}
+		u.Host = m.To
+		return u.String(), true
+	}
+}
+
+// mirrorURLs 获取下载地址的候选列表：按顺序排列的匹配镜像，最后是原始地址
+// 开启镜像探测时，健康的镜像按响应速度排在前面。需要认证的地址（如私有仓库的API地址）不使用镜像，
+// 镜像无法携带令牌，只会得到错误或JSON描述而不是文件内容
+func (c *Client) mirrorURLs(ctx context.Context, rawURL string) []string {
+	if len(c.options.Mirrors) == 0 || c.requiresAuth(rawURL) {
+		return []string{rawURL}
+	}
+
+	var mirrors []string
+	for _, mirror := range c.options.Mirrors {
+		if mirrorURL, ok := mirror.Rewrite(rawURL); ok {
+			mirrors = append(mirrors, mirrorURL)
+		}
+	}
+
+	if c.options.MirrorProbe && len(mirrors) > 1 {
+		mirrors = c.probeMirrors(ctx, mirrors)
+	}
+
+	return append(mirrors, rawURL)
+}
+
+// requiresAuth 检查下载地址是否会由 RequestAuthorizer 添加认证信息
+func (c *Client) requiresAuth(rawURL string) bool {
+	authorizer, ok := c.provider.(RequestAuthorizer)
+	if !ok {
+		return false
+	}
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return false
+	}
+	if err := authorizer.AuthorizeRequest(req); err != nil {
+		// 无法获取令牌时同样不使用镜像，由原始地址的请求返回错误
+		return true
+	}
+	// 新建的请求没有任何请求头，添加了请求头说明该地址需要认证
+	return len(req.Header) > 0
+}
+
+// probeMirrors 并发探测镜像，返回按响应时间排序的地址，不健康的镜像保持原顺序排在最后
+func (c *Client) probeMirrors(ctx context.Context, mirrors []string) []string {
+	type probeResult struct {
+		url     string
+		index   int
+		latency time.Duration
+		healthy bool
+	}
+
+	results := make([]probeResult, len(mirrors))
+	var wg sync.WaitGroup
+	for i, mirrorURL := range mirrors {
+		wg.Add(1)
+		go func(i int, mirrorURL string) {
+			defer wg.Done()
+			latency, healthy := c.probeMirror(ctx, mirrorURL)
+			results[i] = probeResult{url: mirrorURL, index: i, latency: latency, healthy: healthy}
+		}(i, mirrorURL)
+	}
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].healthy != results[j].healthy {
+			return results[i].healthy
+		}
+		if results[i].healthy {
+			return results[i].latency < results[j].latency
+		}
+		return results[i].index < results[j].index
+	})
+
+	sorted := make([]string, len(results))
+	for i, result := range results {
+		sorted[i] = result.url
+		c.logger.Debug("镜像探测结果",
+			zap.String("url", result.url),
+			zap.Bool("healthy", result.healthy),
+			zap.Duration("latency", result.latency),
+		)
+	}
+	return sorted
+}
+
+// probeMirror 使用HEAD请求探测镜像，返回响应时间以及镜像是否可用
+// 不支持HEAD请求（405）的镜像同样视为可用
+func (c *Client) probeMirror(ctx context.Context, mirrorURL string) (time.Duration, bool) {
+	ctx, cancel := context.WithTimeout(ctx, mirrorProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, mirrorURL, nil)
+	if err != nil {
+		return 0, false
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, false
+	}
+	resp.Body.Close()
+
+	healthy := resp.StatusCode < http.StatusBadRequest || resp.StatusCode == http.StatusMethodNotAllowed
+	return time.Since(start), healthy
+}
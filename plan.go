@@ -0,0 +1,221 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// ReleasePlan 表示一次下载的计划，由 Resolve 生成，生成时不下载或写入任何文件
+type ReleasePlan struct {
+	Owner      string        `json:"owner"`             // 仓库所有者
+	Repo       string        `json:"repo"`              // 仓库名称
+	Spec       string        `json:"spec"`              // 请求的版本：为空或latest表示最新版本，也可以是Tag或semver约束
+	Tag        string        `json:"tag"`               // 选中的版本Tag
+	Release    *Release      `json:"release,omitempty"` // 选中的Release，锁定模式下为nil
+	Files      []PlannedFile `json:"files"`             // 将要下载的文件，使用缓存时为空
+	Source     bool          `json:"source"`            // 是否会因没有匹配的资产而下载源代码
+	FromCache  bool          `json:"fromCache"`         // 是否会因已下载最新版本而直接使用缓存的结果
+	TotalBytes int64         `json:"totalBytes"`        // 预计下载的字节数，不包括大小未知的源代码
+	Path       string        `json:"path"`              // 预计的最终路径，与 DownloadResult.Path 相同
+}
+
+// PlannedFile 表示计划下载的一个文件
+type PlannedFile struct {
+	Name         string `json:"name"`         // 文件名
+	URL          string `json:"url"`          // 下载地址（原始地址，不包含镜像）
+	Size         int64  `json:"size"`         // 文件大小（字节），源代码的大小未知时为0
+	Reason       string `json:"reason"`       // 选择该文件的原因
+	DownloadPath string `json:"downloadPath"` // 下载到缓存目录中的路径
+	Extract      bool   `json:"extract"`      // 是否会被解压
+	Path         string `json:"path"`         // 解压并移动到目标目录后的路径
+}
+
+// Resolve 解析将要下载的内容并返回下载计划，只查询Release信息，不下载或写入任何文件
+// spec为空或latest表示最新版本，也可以是Tag或semver约束；计划按Client的全局选项计算，
+// 与使用相同选项调用 DownloadLatestRelease 或 DownloadSpecificRelease 的结果一致
+func (c *Client) Resolve(owner, repo, spec string) (*ReleasePlan, error) {
+	ctx, span := c.startSpan(context.Background(), "Resolve", repoAttr(owner, repo), attrVersion.String(spec))
+	plan, err := c.resolvePlan(ctx, owner, repo, spec, c.defaultFetchOptions())
+	if plan != nil {
+		span.SetAttributes(
+			attrTag.String(plan.Tag),
+			attrPath.String(plan.Path),
+			attrFileCount.Int(len(plan.Files)),
+			attrBytes.Int64(plan.TotalBytes),
+			attrFromCache.Bool(plan.FromCache),
+		)
+	}
+	endSpan(span, err)
+	return plan, err
+}
+
+// resolvePlan 按下载配置生成下载计划
+func (c *Client) resolvePlan(ctx context.Context, owner, repo, spec string, fo fetchOptions) (*ReleasePlan, error) {
+	plan := &ReleasePlan{Owner: owner, Repo: repo, Spec: spec}
+
+	// 锁定模式下计划下载锁文件中记录的文件
+	if c.options.FrozenLockfile {
+		if err := c.planLocked(plan); err != nil {
+			return nil, err
+		}
+	} else {
+		release, err := c.resolveRelease(ctx, owner, repo, spec)
+		if err != nil {
+			return nil, err
+		}
+		plan.Tag, plan.Release = release.TagName, release
+
+		// 已下载最新版本时直接使用缓存的结果
+		if (spec == "" || spec == "latest") && c.options.CheckLatest {
+			if cached := c.loadCachedResult(owner, repo, release.TagName); cached != nil {
+				plan.FromCache, plan.Path = true, cached.Path
+				return plan, nil
+			}
+		}
+
+		if err := c.planRelease(plan, release, fo); err != nil {
+			return nil, err
+		}
+	}
+
+	c.planPaths(plan, fo)
+
+	c.logger.Info("已生成下载计划",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.String("tag", plan.Tag),
+		zap.Int("fileCount", len(plan.Files)),
+		zap.Int64("totalBytes", plan.TotalBytes),
+		zap.Bool("source", plan.Source),
+		zap.String("path", plan.Path),
+	)
+	return plan, nil
+}
+
+// planRelease 按资产选择规则计划要下载的资产，没有匹配的资产且配置了下载源代码时计划下载源代码
+func (c *Client) planRelease(plan *ReleasePlan, release *Release, fo fetchOptions) error {
+	assets, err := c.selectAssets(release, fo.assetPattern)
+	if err != nil {
+		return err
+	}
+
+	if c.shouldDownloadSource(assets) {
+		url := c.provider.SourceArchiveURL(plan.Owner, plan.Repo, plan.Tag)
+		plan.Source = true
+		plan.Files = []PlannedFile{{
+			Name:   sourceFileName(plan.Owner, plan.Repo, plan.Tag, url),
+			URL:    url,
+			Reason: "没有匹配的资产，下载源代码",
+		}}
+		return nil
+	}
+
+	if len(assets) == 0 {
+		return fmt.Errorf("Release %s %w", plan.Tag, ErrNoMatchingAsset)
+	}
+
+	for _, asset := range assets {
+		plan.Files = append(plan.Files, PlannedFile{
+			Name:   asset.Name,
+			URL:    c.getAssetDownloadURL(asset),
+			Size:   asset.Size,
+			Reason: selectionReason(release, fo.assetPattern, asset),
+		})
+	}
+	return nil
+}
+
+// planLocked 计划下载锁文件中记录的文件，spec为具体的Tag时必须与锁定的版本一致
+func (c *Client) planLocked(plan *ReleasePlan) error {
+	tag := plan.Spec
+	if tag == "latest" || isVersionConstraint(tag) {
+		tag = ""
+	}
+
+	entry, err := c.findLockEntry(plan.Owner, plan.Repo, tag)
+	if err != nil {
+		return err
+	}
+	plan.Tag = entry.Tag
+
+	for _, locked := range entry.Assets {
+		plan.Files = append(plan.Files, PlannedFile{Name: locked.Name, URL: locked.URL, Size: locked.Size, Reason: "锁文件中锁定的资产"})
+	}
+	if len(plan.Files) > 0 {
+		return nil
+	}
+
+	if entry.Source == nil {
+		return fmt.Errorf("锁文件中仓库 %s/%s 没有记录文件", plan.Owner, plan.Repo)
+	}
+	plan.Source = true
+	plan.Files = []PlannedFile{{Name: entry.Source.Name, URL: entry.Source.URL, Size: entry.Source.Size, Reason: "锁文件中锁定的源代码"}}
+	return nil
+}
+
+// planPaths 计算每个文件下载、解压和移动到目标目录后的路径，规则与 finalizeFiles 相同
+func (c *Client) planPaths(plan *ReleasePlan, fo fetchOptions) {
+	moveToTarget := fo.targetDir != "" && fo.targetDir != c.options.CacheDir
+
+	// 资产下载到Release的目录中，源代码直接下载到缓存目录
+	downloadDir := c.releaseDir(plan.Owner, plan.Repo, plan.Tag)
+	if plan.Source {
+		downloadDir = c.options.CacheDir
+	}
+
+	// 只有一个文件时直接处理该文件
+	if len(plan.Files) == 1 {
+		file := &plan.Files[0]
+		file.DownloadPath = filepath.Join(downloadDir, file.Name)
+		file.Extract = fo.autoExtract && isSupportedArchive(file.Name)
+		file.Path = file.DownloadPath
+		if file.Extract {
+			file.Path = extractedPath(file.Path)
+		}
+		if moveToTarget {
+			file.Path = filepath.Join(fo.targetDir, filepath.Base(file.Path))
+		}
+		plan.TotalBytes = file.Size
+		plan.Path = file.Path
+		return
+	}
+
+	// 有多个文件时放在同一个目录中
+	dirPath := c.releaseDir(plan.Owner, plan.Repo, plan.Tag)
+	if moveToTarget {
+		dirPath = filepath.Join(fo.targetDir, filepath.Base(dirPath))
+	}
+
+	for i := range plan.Files {
+		file := &plan.Files[i]
+		file.DownloadPath = filepath.Join(downloadDir, file.Name)
+		file.Extract = fo.autoExtract && isSupportedArchive(file.Name)
+		file.Path = filepath.Join(dirPath, file.Name)
+		if file.Extract {
+			file.Path = extractedPath(file.Path)
+		}
+		plan.TotalBytes += file.Size
+	}
+	plan.Path = dirPath
+}
+
+// selectionReason 返回资产被 selectAssets 选中的原因
+func selectionReason(release *Release, pattern string, asset *Asset) string {
+	if pattern != "" {
+		return fmt.Sprintf("名称匹配模式 %s", pattern)
+	}
+	if len(release.Assets) == 1 {
+		return "Release只有一个资产"
+	}
+
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+	if osMatched, archMatched := matchPlatform(strings.ToLower(asset.Name), runtime.GOOS, runtime.GOARCH); osMatched && archMatched {
+		return fmt.Sprintf("匹配当前平台 %s", platform)
+	}
+	return fmt.Sprintf("没有匹配当前平台 %s 的资产，使用第一个资产", platform)
+}
@@ -0,0 +1,64 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ReleaseProvider 定义从代码托管平台获取Release的接口
+// Client默认使用GitHub，可以通过 WithProvider、WithGiteaURL 或 WithGitLabURL 使用其他平台
+type ReleaseProvider interface {
+	// LatestRelease 获取最新的Release，没有Release时返回包装了 ErrNoRelease 的错误
+	LatestRelease(ctx context.Context, owner, repo string) (*Release, error)
+
+	// ReleaseByTag 通过Tag获取Release，Tag不存在时返回包装了 ErrTagNotFound 的错误
+	ReleaseByTag(ctx context.Context, owner, repo, tag string) (*Release, error)
+
+	// ListReleases 获取仓库的所有Release
+	ListReleases(ctx context.Context, owner, repo string) ([]*Release, error)
+
+	// AssetDownloadURL 获取资产的下载URL
+	AssetDownloadURL(asset *Asset) string
+
+	// SourceArchiveURL 获取指定Tag的源代码压缩包URL
+	SourceArchiveURL(owner, repo, tag string) string
+}
+
+// RequestAuthorizer 可选接口，ReleaseProvider实现该接口时，下载资产和源代码前会调用它为请求添加认证信息
+// 实现应只为自身平台的地址添加认证信息，避免将令牌发送给第三方
+type RequestAuthorizer interface {
+	AuthorizeRequest(req *http.Request) error
+}
+
+// AuthenticatedDownloader 可选接口，ReleaseProvider实现该接口时，公开的下载地址返回404（例如私有仓库）时，
+// Client会使用它提供的需要认证的地址重试，认证信息由 RequestAuthorizer 添加
+type AuthenticatedDownloader interface {
+	// AuthenticatedAssetURL 获取资产需要认证的下载地址，没有时返回false
+	AuthenticatedAssetURL(asset *Asset) (string, bool)
+
+	// AuthenticatedSourceArchiveURL 获取源代码压缩包需要认证的下载地址，没有时返回false
+	AuthenticatedSourceArchiveURL(owner, repo, tag string) (string, bool)
+}
+
+// Release 表示一个Release，与具体的代码托管平台无关
+type Release struct {
+	TagName     string    // Tag名称
+	Name        string    // Release名称
+	Body        string    // 发布说明
+	Draft       bool      // 是否为草稿
+	Prerelease  bool      // 是否为预发布版本
+	HTMLURL     string    // Release网页地址
+	PublishedAt time.Time // 发布时间
+	Assets      []*Asset  // 资产列表
+}
+
+// Asset 表示Release中的一个资产
+type Asset struct {
+	ID                 int64  // 资产ID
+	Name               string // 文件名
+	Size               int64  // 文件大小（字节）
+	ContentType        string // 文件类型
+	BrowserDownloadURL string // 浏览器下载地址
+	APIURL             string // API地址
+}
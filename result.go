@@ -0,0 +1,106 @@
+package githubreleasedownloader
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DownloadResult 表示一次下载的结果
+type DownloadResult struct {
+	Owner     string           `json:"owner"`             // 仓库所有者
+	Repo      string           `json:"repo"`              // 仓库名称
+	Tag       string           `json:"tag"`               // 实际下载的版本Tag
+	Release   *Release         `json:"release,omitempty"` // Release元数据，锁定模式下为nil
+	Path      string           `json:"path"`              // 最终路径：单个文件时为该文件（或解压目录），多个文件时为包含所有文件的目录
+	Files     []DownloadedFile `json:"files"`             // 下载的每个文件
+	FromCache bool             `json:"fromCache"`         // 是否因已下载最新版本而直接使用缓存的结果
+	Source    bool             `json:"source"`            // 是否因没有匹配的资产而下载了源代码
+}
+
+// DownloadedFile 表示下载的一个文件
+type DownloadedFile struct {
+	Name          string `json:"name"`                    // 文件名
+	URL           string `json:"url"`                     // 下载地址（原始地址，不包含镜像）
+	Size          int64  `json:"size"`                    // 文件大小（字节）
+	SHA256        string `json:"sha256"`                  // 文件的SHA-256
+	Path          string `json:"path,omitempty"`          // 文件的本地路径，解压成功后压缩包会被删除，此时为空
+	ExtractedPath string `json:"extractedPath,omitempty"` // 解压后的路径，未解压时为空
+}
+
+// finalPath 返回文件处理后的路径，解压时为解压结果，否则为文件本身
+func (f *DownloadedFile) finalPath() string {
+	if f.ExtractedPath != "" {
+		return f.ExtractedPath
+	}
+	return f.Path
+}
+
+// newDownloadedFile 根据下载的文件计算大小和SHA-256
+func newDownloadedFile(url, filePath string) (DownloadedFile, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return DownloadedFile{}, fmt.Errorf("获取文件信息失败: %w", err)
+	}
+
+	sum, err := fileSHA256(filePath)
+	if err != nil {
+		return DownloadedFile{}, err
+	}
+
+	return DownloadedFile{
+		Name:   filepath.Base(filePath),
+		URL:    url,
+		Size:   info.Size(),
+		SHA256: sum,
+		Path:   filePath,
+	}, nil
+}
+
+// rebasePath 将位于oldDir下的路径改为位于newDir下，不在oldDir下时保持不变
+func rebasePath(path, oldDir, newDir string) string {
+	if path == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(oldDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.Join(newDir, rel)
+}
+
+// resultCachePath 获取最新版本下载结果的缓存文件路径
+func (c *Client) resultCachePath(owner, repo string) string {
+	return filepath.Join(c.options.CacheDir, repoFileName(owner, repo)+"-result.json")
+}
+
+// loadCachedResult 读取缓存的最新版本下载结果
+// 缓存的版本与tag不一致或下载的文件已不存在时返回nil
+func (c *Client) loadCachedResult(owner, repo, tag string) *DownloadResult {
+	data, err := os.ReadFile(c.resultCachePath(owner, repo))
+	if err != nil {
+		return nil
+	}
+
+	var result DownloadResult
+	if err := json.Unmarshal(data, &result); err != nil || result.Tag != tag {
+		return nil
+	}
+	if _, err := os.Stat(result.Path); err != nil {
+		return nil
+	}
+
+	result.FromCache = true
+	return &result
+}
+
+// saveCachedResult 保存最新版本的下载结果，用于下次检查是否需要下载
+func (c *Client) saveCachedResult(result *DownloadResult) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化下载结果失败: %w", err)
+	}
+	return os.WriteFile(c.resultCachePath(result.Owner, result.Repo), data, 0644)
+}
@@ -0,0 +1,402 @@
+package githubreleasedownloader
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// smokeTestTimeout 试运行新程序的超时时间
+const smokeTestTimeout = 30 * time.Second
+
+// 校验和文件的后缀，这些文件不会被当作程序包
+var checksumSuffixes = []string{".sha256", ".sha512", ".sha256sum", ".md5", ".txt"}
+
+// 签名、证书和SBOM文件的后缀，这些文件既不是程序包也不是校验和文件
+var signatureSuffixes = []string{".sig", ".asc", ".pem", ".sbom"}
+
+// SelfUpdate 将当前运行的程序更新到最新版本
+// 下载匹配当前平台的压缩包或程序，校验SHA-256后从压缩包中取出程序，原子地替换 os.Executable()，
+// 原程序保留为 <程序>.old 以便 RollbackSelfUpdate 回滚。返回更新后的版本Tag，已是最新版本时返回当前版本
+// Release中没有校验和文件时更新失败，除非设置了 WithSelfUpdateSkipChecksum(true)
+func (c *Client) SelfUpdate(owner, repo, currentVersion string) (string, error) {
+	ctx := context.Background()
+
+	c.logger.Info("开始自更新",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.String("currentVersion", currentVersion),
+	)
+
+	release, err := c.getLatestRelease(ctx, owner, repo)
+	if err != nil {
+		return "", err
+	}
+
+	latestVersion := release.TagName
+	if strings.TrimPrefix(currentVersion, "v") == strings.TrimPrefix(latestVersion, "v") {
+		c.logger.Info("当前已是最新版本，无需更新",
+			zap.String("owner", owner),
+			zap.String("repo", repo),
+			zap.String("version", currentVersion),
+		)
+		return currentVersion, nil
+	}
+
+	exePath, err := executablePath()
+	if err != nil {
+		return "", err
+	}
+
+	// 选择程序包
+	asset := c.selectSelfUpdateAsset(release)
+	if asset == nil {
+		return "", fmt.Errorf("Release %s 中没有当前平台的程序包: %w", latestVersion, ErrNoMatchingAsset)
+	}
+	c.emitAssetsSelected(owner, repo, latestVersion, []*Asset{asset})
+	ctx = withEventScope(ctx, owner, repo, latestVersion)
+
+	filePath, err := c.downloadAsset(ctx, asset, c.releaseDir(owner, repo, latestVersion))
+	if err != nil {
+		return "", err
+	}
+
+	// 校验SHA-256
+	if err := c.verifySelfUpdateChecksum(ctx, release, asset, filePath); err != nil {
+		os.Remove(filePath)
+		return "", err
+	}
+
+	// 从压缩包中取出程序
+	binaryPath := filePath
+	if isSupportedArchive(filePath) {
+		extractedPath, err := c.extractFile(ctx, filePath)
+		if err != nil {
+			return "", fmt.Errorf("解压程序包失败: %w", err)
+		}
+		defer os.RemoveAll(extractedPath)
+
+		binaryPath, err = findExecutable(extractedPath, exePath, repo)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		defer os.Remove(filePath)
+	}
+
+	// 复制到程序所在目录，保证后续重命名在同一文件系统内完成
+	newPath := exePath + ".new"
+	if err := c.copyFile(binaryPath, newPath); err != nil {
+		return "", fmt.Errorf("复制新程序失败: %w", err)
+	}
+	if err := os.Chmod(newPath, 0755); err != nil {
+		os.Remove(newPath)
+		return "", fmt.Errorf("设置新程序权限失败: %w", err)
+	}
+
+	// 试运行新程序
+	if c.options.SelfUpdateSmokeTest {
+		if err := c.smokeTest(newPath); err != nil {
+			os.Remove(newPath)
+			return "", err
+		}
+	}
+
+	if err := c.replaceExecutable(exePath, newPath); err != nil {
+		os.Remove(newPath)
+		return "", err
+	}
+
+	c.logger.Info("自更新成功",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.String("from", currentVersion),
+		zap.String("to", latestVersion),
+		zap.String("path", exePath),
+	)
+
+	return latestVersion, nil
+}
+
+// RollbackSelfUpdate 使用自更新时保留的 <程序>.old 恢复原程序
+func (c *Client) RollbackSelfUpdate() error {
+	exePath, err := executablePath()
+	if err != nil {
+		return err
+	}
+
+	oldPath := exePath + ".old"
+	if _, err := os.Stat(oldPath); err != nil {
+		return fmt.Errorf("没有可回滚的程序: %w", err)
+	}
+
+	if err := c.replaceExecutable(exePath, oldPath); err != nil {
+		return err
+	}
+
+	c.logger.Info("已回滚自更新", zap.String("path", exePath))
+
+	return nil
+}
+
+// selectSelfUpdateAsset 选择名称同时匹配当前操作系统和架构的程序包
+// 只接受可以解压的压缩包或未打包的程序，跳过校验和与签名文件；没有匹配的资产时返回nil，
+// 不会像 getReleaseAssets 一样回退到第一个资产，避免用安装包或其他平台的程序替换当前程序
+func (c *Client) selectSelfUpdateAsset(release *Release) *Asset {
+	for _, asset := range release.Assets {
+		if isChecksumFile(asset.Name) || !(isSupportedArchive(asset.Name) || isRawBinary(asset.Name)) {
+			continue
+		}
+		if osMatched, archMatched := matchPlatform(strings.ToLower(asset.Name), runtime.GOOS, runtime.GOARCH); osMatched && archMatched {
+			return asset
+		}
+	}
+	return nil
+}
+
+// verifySelfUpdateChecksum 使用Release中的校验和文件校验程序包
+// 支持 <资产名>.sha256 以及 checksums.txt、SHA256SUMS 等汇总文件，跳过签名和证书文件
+// 没有校验和文件时返回错误，设置了 WithSelfUpdateSkipChecksum(true) 时跳过校验
+func (c *Client) verifySelfUpdateChecksum(ctx context.Context, release *Release, asset *Asset, filePath string) error {
+	var checksumAsset *Asset
+	for _, a := range release.Assets {
+		if isSignatureFile(a.Name) {
+			continue
+		}
+		lowerName := strings.ToLower(a.Name)
+		if lowerName == strings.ToLower(asset.Name)+".sha256" {
+			checksumAsset = a
+			break
+		}
+		if checksumAsset == nil && (strings.Contains(lowerName, "checksums") || strings.Contains(lowerName, "sha256sums")) {
+			checksumAsset = a
+		}
+	}
+
+	if checksumAsset == nil {
+		if !c.options.SelfUpdateSkipChecksum {
+			return fmt.Errorf("Release %s 中没有 %s 的校验和文件", release.TagName, asset.Name)
+		}
+		c.logger.Warn("Release中没有校验和文件，跳过校验",
+			zap.String("tag", release.TagName),
+			zap.String("asset", asset.Name),
+		)
+		return nil
+	}
+
+	checksumPath, err := c.downloadAsset(ctx, checksumAsset, filepath.Dir(filePath))
+	if err != nil {
+		return fmt.Errorf("下载校验和文件失败: %w", err)
+	}
+	defer os.Remove(checksumPath)
+
+	expected, err := findChecksum(checksumPath, asset.Name, strings.EqualFold(checksumAsset.Name, asset.Name+".sha256"))
+	if err != nil {
+		return err
+	}
+
+	actual, err := fileSHA256(filePath)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(expected, actual) {
+		return &ChecksumMismatchError{Name: asset.Name, Expected: expected, Actual: actual}
+	}
+
+	c.logger.Info("SHA-256校验通过",
+		zap.String("asset", asset.Name),
+		zap.String("sha256", actual),
+	)
+	c.emit(scopedEvent(ctx, Event{Type: EventChecksumVerified, Tag: release.TagName, Asset: asset.Name, Path: filePath}))
+
+	return nil
+}
+
+// smokeTest 以--version试运行新程序
+func (c *Client) smokeTest(binaryPath string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), smokeTestTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, binaryPath, "--version").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("试运行新程序失败: %w", err)
+	}
+
+	c.logger.Info("试运行新程序成功",
+		zap.String("path", binaryPath),
+		zap.String("output", strings.TrimSpace(string(output))),
+	)
+
+	return nil
+}
+
+// findChecksum 从校验和文件中查找指定文件的SHA-256
+// 支持 "<hash>  <文件名>" 格式的汇总文件；single为true时（<资产名>.sha256）也支持只包含hash的格式
+func findChecksum(checksumPath, name string, single bool) (string, error) {
+	file, err := os.Open(checksumPath)
+	if err != nil {
+		return "", fmt.Errorf("打开校验和文件失败: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch len(fields) {
+		case 1:
+			if single {
+				return fields[0], nil
+			}
+		case 2:
+			// sha256sum的二进制模式会在文件名前加*
+			if strings.TrimPrefix(fields[1], "*") == name {
+				return fields[0], nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("读取校验和文件失败: %w", err)
+	}
+
+	return "", fmt.Errorf("校验和文件中没有 %s 的记录", name)
+}
+
+// findExecutable 在解压目录中查找与当前程序或仓库同名的可执行文件
+func findExecutable(dir, exePath, repo string) (string, error) {
+	candidates := map[string]bool{
+		filepath.Base(exePath): true,
+		repo:                   true,
+	}
+	if runtime.GOOS == "windows" {
+		candidates[repo+".exe"] = true
+	}
+
+	var found string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && candidates[d.Name()] {
+			found = path
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("查找程序失败: %w", err)
+	}
+
+	// 没有同名文件时，如果压缩包中只有一个文件则使用它
+	if found == "" {
+		var files []string
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && d.Type().IsRegular() {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if len(files) == 1 {
+			found = files[0]
+		}
+	}
+
+	if found == "" {
+		return "", fmt.Errorf("程序包中没有找到可执行文件 %s", filepath.Base(exePath))
+	}
+
+	return found, nil
+}
+
+// replaceExecutable 用newPath原子地替换exePath，原程序保留为 <exePath>.old
+// 先通过硬链接（不支持时复制）保留原程序，再用一次重命名替换，替换过程中exePath始终存在
+func (c *Client) replaceExecutable(exePath, newPath string) error {
+	oldPath := exePath + ".old"
+	backupPath := exePath + ".bak"
+
+	// 回滚时newPath就是oldPath，需要先把它移开
+	rollback := newPath == oldPath
+	if rollback {
+		if err := os.Rename(oldPath, backupPath); err != nil {
+			return fmt.Errorf("移动旧程序失败: %w", err)
+		}
+		newPath = backupPath
+	} else if err := os.Remove(oldPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除旧的备份程序失败: %w", err)
+	}
+
+	// 失败时恢复回滚前的旧程序
+	restore := func() {
+		if rollback {
+			os.Rename(backupPath, oldPath)
+		}
+	}
+
+	if err := os.Link(exePath, oldPath); err != nil {
+		if err := c.copyFile(exePath, oldPath); err != nil {
+			os.Remove(oldPath)
+			restore()
+			return fmt.Errorf("备份当前程序失败: %w", err)
+		}
+	}
+
+	if err := os.Rename(newPath, exePath); err != nil {
+		restore()
+		return fmt.Errorf("替换程序失败: %w", err)
+	}
+
+	return nil
+}
+
+// executablePath 获取当前程序的真实路径
+func executablePath() (string, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("获取当前程序路径失败: %w", err)
+	}
+
+	exePath, err = filepath.EvalSymlinks(exePath)
+	if err != nil {
+		return "", fmt.Errorf("解析当前程序路径失败: %w", err)
+	}
+
+	return exePath, nil
+}
+
+// isChecksumFile 检查文件是否为校验和或签名文件
+func isChecksumFile(name string) bool {
+	lowerName := strings.ToLower(name)
+	for _, suffix := range checksumSuffixes {
+		if strings.HasSuffix(lowerName, suffix) {
+			return true
+		}
+	}
+	return isSignatureFile(name) || strings.Contains(lowerName, "checksums") || strings.Contains(lowerName, "sha256sums")
+}
+
+// isSignatureFile 检查文件是否为签名、证书或SBOM文件
+func isSignatureFile(name string) bool {
+	lowerName := strings.ToLower(name)
+	for _, suffix := range signatureSuffixes {
+		if strings.HasSuffix(lowerName, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRawBinary 检查资产是否为未打包的程序：没有扩展名（版本号中的点不算）或为 .exe
+// .deb、.rpm、.msi、.tar.xz 等安装包和不支持的压缩包不是程序
+func isRawBinary(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == "" || ext == ".exe" || strings.ContainsAny(ext, "_-") || strings.Trim(ext, ".0123456789") == ""
+}
@@ -0,0 +1,166 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// OpenAsset 打开Release中指定名称的资产，返回的reader直接读取网络响应，不写入缓存目录
+// tag为空时使用最新版本，也可以是semver约束。与文件下载一样使用访问令牌、代理、镜像和带宽限制，
+// 镜像只在开始读取之前切换。调用方读取完毕后必须关闭返回的reader
+func (c *Client) OpenAsset(ctx context.Context, owner, repo, tag, assetName string) (io.ReadCloser, error) {
+	reader, err := c.openAsset(ctx, owner, repo, tag, assetName)
+	if err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+// StreamAsset 将Release中指定名称的资产写入w，不写入缓存目录，返回写入的字节数
+// tag为空时使用最新版本，参见 OpenAsset
+func (c *Client) StreamAsset(ctx context.Context, owner, repo, tag, assetName string, w io.Writer) (int64, error) {
+	reader, err := c.openAsset(ctx, owner, repo, tag, assetName)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := io.Copy(w, reader)
+	if err != nil {
+		reader.fail(err)
+		reader.Close()
+		return n, fmt.Errorf("传输资产 %s 失败: %w", assetName, err)
+	}
+
+	return n, reader.Close()
+}
+
+// openAsset 查找资产并打开下载响应
+func (c *Client) openAsset(ctx context.Context, owner, repo, tag, assetName string) (*assetReader, error) {
+	ctx, span := c.startSpan(ctx, "OpenAsset", repoAttr(owner, repo), attrTag.String(tag), attrAsset.String(assetName))
+
+	asset, releaseTag, err := c.findAsset(ctx, owner, repo, tag, assetName)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attrAssetSize.Int64(asset.Size))
+
+	start := time.Now()
+	url := c.getAssetDownloadURL(asset)
+	resp, err := c.openWithMirrors(ctx, url)
+	authURL := func(d AuthenticatedDownloader) (string, bool) { return d.AuthenticatedAssetURL(asset) }
+	if fallbackURL, ok := c.authFallbackURL(err, url, authURL); ok {
+		resp, err = c.openWithMirrors(ctx, fallbackURL)
+	}
+	if err != nil {
+		c.metrics.ObserveDownload(time.Since(start), err)
+		endSpan(span, err)
+		return nil, err
+	}
+
+	c.emit(Event{Type: EventDownloadStarted, Owner: owner, Repo: repo, Tag: releaseTag, Asset: asset.Name, URL: resp.Request.URL.String(), Total: resp.ContentLength})
+
+	return &assetReader{
+		client: c,
+		reader: c.limitBandwidth(ctx, resp.Body),
+		body:   resp.Body,
+		span:   span,
+		start:  start,
+		progress: &progressEmitter{
+			client: c,
+			event:  Event{Type: EventDownloadProgress, Owner: owner, Repo: repo, Tag: releaseTag, Asset: asset.Name, URL: resp.Request.URL.String(), Total: resp.ContentLength},
+		},
+	}, nil
+}
+
+// findAsset 解析Release并查找指定名称的资产，同时返回Release的Tag
+func (c *Client) findAsset(ctx context.Context, owner, repo, tag, assetName string) (*Asset, string, error) {
+	release, err := c.resolveRelease(ctx, owner, repo, tag)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, asset := range release.Assets {
+		if asset.Name == assetName {
+			c.emitAssetsSelected(owner, repo, release.TagName, []*Asset{asset})
+			return asset, release.TagName, nil
+		}
+	}
+
+	return nil, "", fmt.Errorf("Release %s 中没有名为 %s 的资产: %w", release.TagName, assetName, ErrNoMatchingAsset)
+}
+
+// openWithMirrors 依次尝试镜像和原始地址，返回第一个成功的响应
+func (c *Client) openWithMirrors(ctx context.Context, url string) (*http.Response, error) {
+	candidates := c.mirrorURLs(ctx, url)
+
+	var err error
+	for i, candidate := range candidates {
+		var resp *http.Response
+		if resp, err = c.openURL(ctx, candidate); err == nil {
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		if i < len(candidates)-1 {
+			c.logger.Warn("打开下载地址失败，尝试下一个地址",
+				zap.String("url", candidate),
+				zap.String("next", candidates[i+1]),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return nil, err
+}
+
+// assetReader 读取资产的响应体，关闭时记录指标并结束Span
+type assetReader struct {
+	client   *Client
+	reader   io.Reader
+	body     io.Closer
+	span     Span
+	start    time.Time
+	progress *progressEmitter
+	bytes    int64
+	err      error
+	once     sync.Once
+}
+
+// Read 实现 io.Reader
+func (r *assetReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.bytes += int64(n)
+	r.progress.update(r.bytes)
+	if err != nil && err != io.EOF {
+		r.fail(err)
+	}
+	return n, err
+}
+
+// fail 记录读取或写入失败的原因
+func (r *assetReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// Close 关闭响应体，记录下载的字节数和结果
+func (r *assetReader) Close() error {
+	err := r.body.Close()
+	r.once.Do(func() {
+		r.progress.flush()
+		r.client.metrics.AddDownloadBytes(r.bytes)
+		r.client.metrics.ObserveDownload(time.Since(r.start), r.err)
+		r.span.SetAttributes(attrBytes.Int64(r.bytes))
+		endSpan(r.span, r.err)
+	})
+	return err
+}
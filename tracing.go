@@ -0,0 +1,135 @@
+package githubreleasedownloader
+
+import (
+	"context"
+	"net/http"
+)
+
+// Tracer 记录解析、下载、解压和移动文件的Span，通过 WithTracer 设置
+// OpenTelemetry的实现见 tracing 子包，核心包不依赖任何链路追踪库
+type Tracer interface {
+	// Start 创建子Span，返回的context包含该Span，父Span从ctx中获取
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span 表示一个正在记录的操作
+type Span interface {
+	// SetAttributes 设置Span的属性
+	SetAttributes(attrs ...Attribute)
+
+	// End 结束Span，err不为nil时将Span标记为失败并记录错误
+	End(err error)
+}
+
+// TransportWrapper 可选接口，Tracer实现该接口时用它包装所有请求使用的HTTP Transport，
+// 例如将每个HTTP请求记录为当前步骤的子Span
+type TransportWrapper interface {
+	WrapTransport(base http.RoundTripper) http.RoundTripper
+}
+
+// Attribute Span的属性，Value的类型为string、int、int64或bool
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// attributeKey Span属性的键
+type attributeKey string
+
+// String 创建字符串属性
+func (k attributeKey) String(v string) Attribute { return Attribute{Key: string(k), Value: v} }
+
+// Int 创建整数属性
+func (k attributeKey) Int(v int) Attribute { return Attribute{Key: string(k), Value: v} }
+
+// Int64 创建64位整数属性
+func (k attributeKey) Int64(v int64) Attribute { return Attribute{Key: string(k), Value: v} }
+
+// Bool 创建布尔属性
+func (k attributeKey) Bool(v bool) Attribute { return Attribute{Key: string(k), Value: v} }
+
+// Span属性的键
+const (
+	attrRepo         = attributeKey("grd.repo")
+	attrTag          = attributeKey("grd.tag")
+	attrVersion      = attributeKey("grd.version")
+	attrAsset        = attributeKey("grd.asset")
+	attrAssetSize    = attributeKey("grd.asset.size")
+	attrBytes        = attributeKey("grd.bytes")
+	attrPath         = attributeKey("grd.path")
+	attrSource       = attributeKey("grd.source")
+	attrFileCount    = attributeKey("grd.file_count")
+	attrFromCache    = attributeKey("grd.from_cache")
+	attrRequestCount = attributeKey("grd.request_count")
+	attrReleaseCount = attributeKey("grd.release_count")
+)
+
+// noopTracer 未设置 WithTracer 时使用，不记录任何Span
+type noopTracer struct{}
+
+// Start 实现 Tracer
+func (noopTracer) Start(ctx context.Context, _ string, _ ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// noopSpan 不记录任何内容的Span
+type noopSpan struct{}
+
+// SetAttributes 实现 Span
+func (noopSpan) SetAttributes(...Attribute) {}
+
+// End 实现 Span
+func (noopSpan) End(error) {}
+
+// newTracer 返回配置的Tracer，未设置时返回不记录Span的实现
+func newTracer(options *Options) Tracer {
+	if options.Tracer == nil {
+		return noopTracer{}
+	}
+	return options.Tracer
+}
+
+// spanKey 在context中保存当前Span的键
+type spanKey struct{}
+
+// startSpan 创建子Span，并将其保存到context中供 spanFromContext 使用
+func (c *Client) startSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	ctx, span := c.tracer.Start(ctx, name, attrs...)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// spanFromContext 获取context中的当前Span，没有时返回不记录内容的Span
+func spanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanKey{}).(Span); ok {
+		return span
+	}
+	return noopSpan{}
+}
+
+// endSpan 记录错误并结束Span
+func endSpan(span Span, err error) {
+	span.End(err)
+}
+
+// endDownloadSpan 记录下载结果并结束 Download* 方法的Span
+func endDownloadSpan(span Span, result *DownloadResult, err error) {
+	if result != nil {
+		var bytes int64
+		for _, file := range result.Files {
+			bytes += file.Size
+		}
+		span.SetAttributes(
+			attrTag.String(result.Tag),
+			attrPath.String(result.Path),
+			attrFileCount.Int(len(result.Files)),
+			attrBytes.Int64(bytes),
+			attrFromCache.Bool(result.FromCache),
+		)
+	}
+	endSpan(span, err)
+}
+
+// repoAttr 返回仓库属性，格式为 owner/repo
+func repoAttr(owner, repo string) Attribute {
+	return attrRepo.String(owner + "/" + repo)
+}
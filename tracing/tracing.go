@@ -0,0 +1,86 @@
+// Package tracing 提供 github-release-downloader 的OpenTelemetry链路追踪
+// 核心包只依赖 Tracer 接口，不使用链路追踪时不会引入OpenTelemetry的依赖
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	githubreleasedownloader "github.com/sunwu57/github-release-downloader"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName OpenTelemetry的instrumentation名称
+const tracerName = "github.com/sunwu57/github-release-downloader"
+
+var (
+	_ githubreleasedownloader.Tracer           = (*Tracer)(nil)
+	_ githubreleasedownloader.TransportWrapper = (*Tracer)(nil)
+)
+
+// Tracer 使用OpenTelemetry记录Span，实现了 githubreleasedownloader.Tracer
+// 同时使用otelhttp将每个HTTP请求记录为对应步骤的子Span
+type Tracer struct {
+	provider trace.TracerProvider
+	tracer   trace.Tracer
+}
+
+// New 使用指定的TracerProvider创建Tracer，不会使用全局的TracerProvider
+func New(tp trace.TracerProvider) *Tracer {
+	return &Tracer{provider: tp, tracer: tp.Tracer(tracerName)}
+}
+
+// Start 实现 githubreleasedownloader.Tracer
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...githubreleasedownloader.Attribute) (context.Context, githubreleasedownloader.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(convertAttributes(attrs)...))
+	return ctx, &otelSpan{span: span}
+}
+
+// WrapTransport 实现 githubreleasedownloader.TransportWrapper
+func (t *Tracer) WrapTransport(base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(base, otelhttp.WithTracerProvider(t.provider))
+}
+
+// otelSpan 包装OpenTelemetry的Span
+type otelSpan struct {
+	span trace.Span
+}
+
+// SetAttributes 实现 githubreleasedownloader.Span
+func (s *otelSpan) SetAttributes(attrs ...githubreleasedownloader.Attribute) {
+	s.span.SetAttributes(convertAttributes(attrs)...)
+}
+
+// End 实现 githubreleasedownloader.Span
+func (s *otelSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// convertAttributes 将属性转换为OpenTelemetry的属性
+func convertAttributes(attrs []githubreleasedownloader.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		key := attribute.Key(attr.Key)
+		switch v := attr.Value.(type) {
+		case string:
+			kvs = append(kvs, key.String(v))
+		case int:
+			kvs = append(kvs, key.Int(v))
+		case int64:
+			kvs = append(kvs, key.Int64(v))
+		case bool:
+			kvs = append(kvs, key.Bool(v))
+		default:
+			kvs = append(kvs, key.String(fmt.Sprint(v)))
+		}
+	}
+	return kvs
+}